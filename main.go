@@ -1,23 +1,47 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"github.com/sky-uk/licence-compliance-checker/pkg/compliance"
+	"github.com/sky-uk/licence-compliance-checker/pkg/dep"
 	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"github.com/sky-uk/licence-compliance-checker/pkg/gomodules"
+	"github.com/sky-uk/licence-compliance-checker/pkg/licencelist"
+	"github.com/sky-uk/licence-compliance-checker/pkg/plugin"
+	"github.com/sky-uk/licence-compliance-checker/pkg/policy"
+	"github.com/sky-uk/licence-compliance-checker/pkg/resolver"
 	"github.com/spf13/cobra"
 	"io/ioutil"
 	"os"
-	"os/exec"
-	"strings"
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "licence-compliance-checker",
 	Short: "Check licences compliance based on list of restricted licences",
-	Run:   validateCompliance,
+	// positional args are the project directories, rather than subcommands
+	Args: cobra.ArbitraryArgs,
+	Run:  validateCompliance,
+}
+
+var licenceListCmd = &cobra.Command{
+	Use:   "licence-list",
+	Short: "Manage the SPDX licence list imported to detect the licences unknown to the embedded licence database",
+}
+
+var importLicenceListCmd = &cobra.Command{
+	Use:   "import <license-list-data directory>",
+	Short: "Import the SPDX licence list of a local license-list-data bundle into the licence list cache",
+	Args:  cobra.ExactArgs(1),
+	Run:   importLicenceList,
+}
+
+var licenceListVersionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the version of the SPDX licence list of the licence list cache",
+	Args:  cobra.NoArgs,
+	Run:   showLicenceListVersion,
 }
 
 var (
@@ -29,6 +53,27 @@ var (
 	showComplianceErrors     bool
 	showComplianceAll        bool
 	checkGoModules           bool
+	checkReplacedModules     bool
+	checkVendoredModules     bool
+	useModuleZips            bool
+	checkBinary              string
+	checkNestedLicences      bool
+	fetchMissingModules      bool
+	checkDepProjects         bool
+	vendorDir                string
+	checkNpmPackages         string
+	checkPythonPackages      string
+	checkMavenDependencies   string
+	mavenRepository          string
+	checkCargoCrates         string
+	checkOSPackages          string
+	policyFile               string
+	detectSPDXHeaders        bool
+	deepScan                 bool
+	extractCopyrights        bool
+	licenceListCache         string
+	directSeverity           string
+	indirectSeverity         string
 )
 
 func main() {
@@ -41,12 +86,36 @@ func init() {
 	rootCmd.PersistentFlags().StringSliceVarP(&ignoredProjects, "ignore-project", "i", []string{}, "project which licence will not be checked for compliance. Repeat this flag to specify multiple values.")
 	rootCmd.PersistentFlags().StringToStringVarP(&overriddenLicences, "override-licence", "o", map[string]string{}, "can be used to override the licence detected for a project directory - e.g. vendor/github.com/spf13/cobra=MIT. Repeat this flag to specify multiple values.")
 	rootCmd.PersistentFlags().StringToStringVarP(&overriddenModuleLicences, "override-module-licence", "m", map[string]string{}, "can be used to override the licence detected for a go module - e.g. github.com/spf13/cobra=MIT. Repeat this flag to specify multiple values.")
-	rootCmd.PersistentFlags().StringSliceVarP(&restrictedLicences, "restricted-licence", "r", []string{}, "licence that will fail the compliance check if found for a project. Repeat this flag to specify multiple values.")
+	rootCmd.Flags().StringSliceVarP(&restrictedLicences, "restricted-licence", "r", []string{}, "licence that will fail the compliance check if found for a project. Repeat this flag to specify multiple values.")
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "L", "", "(output) should be one of: (none), debug, info, warn, error, fatal, panic. default (none)")
 	rootCmd.PersistentFlags().BoolVarP(&showComplianceAll, "show-compliance-all", "A", false, "(output) to show compliance checks as JSON regardless of outcome")
 	rootCmd.PersistentFlags().BoolVarP(&showComplianceErrors, "show-compliance-errors", "E", false, "(output) to show compliance checks as JSON only in case of errors")
 	rootCmd.PersistentFlags().BoolVarP(&checkGoModules, "check-go-modules", "", false, "check all go modules a project depends on. This replaces specifying multiple project directories as positional arguments.")
-	rootCmd.MarkPersistentFlagRequired("restricted-licence")
+	rootCmd.PersistentFlags().BoolVarP(&checkReplacedModules, "check-replaced-modules", "", false, "with --check-go-modules, also detect the licence of go modules replaced by a replace directive and report when it differs from the licence of their replacement. The original modules must be in the module cache.")
+	rootCmd.PersistentFlags().BoolVarP(&checkVendoredModules, "check-vendored-modules", "", false, "check all go modules listed in vendor/modules.txt, as created by `go mod vendor`. This replaces specifying multiple project directories as positional arguments.")
+	rootCmd.PersistentFlags().BoolVarP(&useModuleZips, "use-module-zips", "", false, "with --check-go-modules, detect licences straight from the module zips of the module download cache rather than from the extracted modules. The zips hashes are checked against go.sum.")
+	rootCmd.PersistentFlags().StringVarP(&checkBinary, "check-binary", "", "", "check all go modules embedded in the build information of the given go executable. The modules are looked up in the module cache and in the file:// GOPROXY directories. This replaces specifying multiple project directories as positional arguments.")
+	rootCmd.PersistentFlags().BoolVarP(&checkNestedLicences, "check-nested-licences", "", false, "also check the subdirectories of each project which contain their own licence file, e.g. a bundled third_party directory. They are reported as sub-components of their parent project.")
+	rootCmd.PersistentFlags().BoolVarP(&fetchMissingModules, "fetch-missing-modules", "", false, "with --check-go-modules or --check-binary, fetch the go modules which have not been downloaded from the file:// and http(s):// proxies of GOPROXY. Modules which cannot be fetched are reported as unresolved.")
+	rootCmd.PersistentFlags().BoolVarP(&checkDepProjects, "check-dep-projects", "", false, "check all projects locked in Gopkg.lock, from their vendor directory, as managed by `dep`. This replaces specifying multiple project directories as positional arguments.")
+	rootCmd.PersistentFlags().StringVarP(&vendorDir, "vendor-dir", "", "", "check all repository roots found in the given vendor directory, e.g. vendor/github.com/spf13/cobra. Directories which contain files but neither a licence nor a known repository root are reported as orphaned. This replaces specifying multiple project directories as positional arguments.")
+	rootCmd.PersistentFlags().StringVarP(&checkNpmPackages, "check-npm-packages", "", "", "also check all npm packages locked in the package-lock.json (v2 or v3) of the given directory, from its node_modules directory. It can be used along with the other options, or on its own.")
+	rootCmd.PersistentFlags().StringVarP(&checkPythonPackages, "check-python-packages", "", "", "also check all python distributions installed into the given site-packages or virtualenv directory. It can be used along with the other options, or on its own.")
+	rootCmd.PersistentFlags().StringVarP(&checkMavenDependencies, "check-maven-dependencies", "", "", "also check the maven artifacts of the given pom.xml (direct dependencies) or dependency list (as written by `mvn dependency:list -DoutputFile=<file>`), from the local maven repository. It can be used along with the other options, or on its own.")
+	rootCmd.PersistentFlags().StringVarP(&mavenRepository, "maven-repository", "", "", "with --check-maven-dependencies, the local maven repository the artifacts are read from. default (~/.m2/repository)")
+	rootCmd.PersistentFlags().StringVarP(&checkCargoCrates, "check-cargo-crates", "", "", "also check all rust crates locked in the given Cargo.lock, or in the Cargo.lock of the given directory, from the cargo registry cache of CARGO_HOME (default ~/.cargo). It can be used along with the other options, or on its own.")
+	rootCmd.PersistentFlags().StringVarP(&checkOSPackages, "check-os-packages", "", "", "also check the OS packages installed in the given root filesystem directory or image tarball (e.g. from `docker save`), from the dpkg status database and the machine-readable copyright files, or the apk installed database. OS packages are only checked against the licences they declare. It can be used along with the other options, or on its own.")
+	rootCmd.PersistentFlags().StringVarP(&policyFile, "policy", "", "", "JSON policy file configuring the external detector and resolver plugins, and the detection strategy. By default, detector plugins detect the licences the built-in detector cannot, and the projects of resolver plugins are checked along with the other options.")
+	rootCmd.PersistentFlags().StringVarP(&licenceListCache, "licence-list-cache", "", "", "directory of the SPDX licence list imported with `licence-list import`, whose licences unknown to the embedded licence database are detected along with the embedded licences. default (licence-compliance-checker/licence-list of the user cache directory)")
+	rootCmd.PersistentFlags().BoolVarP(&deepScan, "deep-scan", "", false, "also identify the licence of each file of projects, from their SPDX-License-Identifier header, nested licence file or licence notice, and check the files whose licence differs from the project licence against the restricted licences. default (false)")
+	rootCmd.PersistentFlags().BoolVarP(&extractCopyrights, "extract-copyrights", "", false, "also extract the copyright holders and years of projects from the copyright statements of their licence files, NOTICE files and file headers. default (false)")
+	rootCmd.PersistentFlags().BoolVarP(&detectSPDXHeaders, "detect-spdx-headers", "", false, "detect the licence of projects without licence files from the SPDX-License-Identifier headers of their files, and report the files whose header differs from the project licence. default (false)")
+	rootCmd.PersistentFlags().StringVarP(&directSeverity, "direct-severity", "", "", "severity of the restricted, unidentifiable and unresolved go modules which are direct dependencies: error fails the compliance check, warning only reports them. Should be one of: error, warning. Defaults to the directSeverity of the policy, or else error.")
+	rootCmd.PersistentFlags().StringVarP(&indirectSeverity, "indirect-severity", "", "", "severity of the restricted, unidentifiable and unresolved go modules which are indirect dependencies: error fails the compliance check, warning only reports them. Should be one of: error, warning. Defaults to the indirectSeverity of the policy, or else error.")
+	rootCmd.MarkFlagRequired("restricted-licence")
+
+	rootCmd.AddCommand(licenceListCmd)
+	licenceListCmd.AddCommand(importLicenceListCmd, licenceListVersionCmd)
 }
 
 func validateCompliance(_ *cobra.Command, args []string) {
@@ -56,59 +125,226 @@ func validateCompliance(_ *cobra.Command, args []string) {
 		logAndExit("Only use one of --override-module-licence (%d uses) and --override-licence (%d uses)", len(overriddenModuleLicences), len(overriddenLicences))
 	}
 
-	for module, licence := range overriddenModuleLicences {
-		cmd := exec.Command("go", "list", "-m", "-f", "\"{{.Dir}}\"", module)
+	config := compliance.Config{
+		RestrictedLicences:        restrictedLicences,
+		IgnoredProjects:           ignoredProjects,
+		OverriddenProjectLicences: overriddenLicences,
+		OverriddenModuleLicences:  overriddenModuleLicences,
+	}
 
-		var out bytes.Buffer
-		var stderr bytes.Buffer
-		cmd.Stdout = &out
-		cmd.Stderr = &stderr
+	if checkReplacedModules && !checkGoModules {
+		logAndExit("--check-replaced-modules can only be used with --check-go-modules")
+	}
 
-		err := cmd.Run()
-		if err != nil {
-			logAndExit("Failed to find directory for go module %s: %s %s (try setting GO111MODULE=on)", module, stderr.String(), err)
+	if fetchMissingModules && !checkGoModules && checkBinary == "" {
+		logAndExit("--fetch-missing-modules can only be used with --check-go-modules or --check-binary")
+	}
+
+	if mavenRepository != "" && checkMavenDependencies == "" {
+		logAndExit("--maven-repository can only be used with --check-maven-dependencies")
+	}
+
+	if useModuleZips && !checkGoModules {
+		logAndExit("--use-module-zips can only be used with --check-go-modules")
+	}
+
+	var inputModes int
+	for _, inputMode := range []bool{checkGoModules, checkVendoredModules, checkBinary != "", checkDepProjects, vendorDir != ""} {
+		if inputMode {
+			inputModes++
 		}
+	}
+	if inputModes > 1 {
+		logAndExit("Only use one of --check-go-modules, --check-vendored-modules, --check-binary, --check-dep-projects and --vendor-dir")
+	}
 
-		pkgDir := strings.Trim(strings.TrimSpace(out.String()), "\"")
-		overriddenLicences[pkgDir] = licence
+	var err error
+	checkPolicy := &policy.Policy{}
+	if policyFile != "" {
+		if checkPolicy, err = policy.Load(policyFile); err != nil {
+			logAndExit("Failed to load policy: %s", err)
+		}
+	}
+	config.MismatchSeverity = compliance.Severity(checkPolicy.MismatchSeverity)
+	config.DirectSeverity = compliance.Severity(checkPolicy.DirectSeverity)
+	if directSeverity != "" {
+		if config.DirectSeverity, err = compliance.ParseSeverity(directSeverity); err != nil {
+			logAndExit("Invalid --direct-severity: %s", err)
+		}
+	}
+	config.IndirectSeverity = compliance.Severity(checkPolicy.IndirectSeverity)
+	if indirectSeverity != "" {
+		if config.IndirectSeverity, err = compliance.ParseSeverity(indirectSeverity); err != nil {
+			logAndExit("Invalid --indirect-severity: %s", err)
+		}
 	}
 
-	config := compliance.Config{
-		RestrictedLicences:        restrictedLicences,
-		IgnoredProjects:           ignoredProjects,
-		OverriddenProjectLicences: overriddenLicences,
+	if detectSPDXHeaders && checkPolicy.Detection != nil {
+		logAndExit("--detect-spdx-headers and a policy detection cannot be set at the same time, add the %s detector to the policy detection instead", detection.DetectorSPDXHeaders)
 	}
 
+	if inputModes == 0 && checkNpmPackages == "" && checkPythonPackages == "" && checkMavenDependencies == "" && checkCargoCrates == "" && checkOSPackages == "" && len(checkPolicy.Resolvers()) == 0 && len(args) == 0 {
+		logAndExit("requires at least 1 arg (received %d)", len(args))
+	}
+
+	licenceList, err := licencelist.Load(licenceListCacheDir())
+	if err != nil {
+		logAndExit("Failed to load the licence list: %s", err)
+	}
+	var templates []detection.LicenceTemplate
+	if licenceList != nil {
+		log.Infof("Using SPDX licence list %s, with %d licences unknown to the embedded licence database", licenceList.Version, len(licenceList.Licences))
+		detection.AddSPDXLicences(licenceList.IDs()...)
+		if templates, err = licenceList.Templates(); err != nil {
+			logAndExit("Failed to load the licence list: %s", err)
+		}
+	}
+	if checkPolicy.LicenceTemplates != "" {
+		policyTemplates, err := detection.LoadLicenceTemplates(checkPolicy.LicenceTemplates)
+		if err != nil {
+			logAndExit("Failed to load licence templates: %s", err)
+		}
+		templates = append(templates, policyTemplates...)
+	}
+	licenceDetector := detection.NewLicenceDetector()
+	if len(templates) > 0 {
+		licenceDetector = detection.NewLicenceDetectorWithTemplates(templates)
+	}
+	var resolvers resolver.Resolvers
+	var vendoredModules *resolver.VendoredModules
+	var vendorDirProjects *resolver.VendorDirProjects
 	if checkGoModules {
 		if len(args) > 0 {
 			logAndExit("--check-go-modules and positional args cannot be set at the same time (received %d)", len(args))
 		}
 
-		var err error
-		args, err = getGoModules()
-		if err != nil {
-			logAndExit("Failed to list go modules: %s", err)
+		resolvers.Register(&resolver.GoModules{
+			UseModuleZips:        useModuleZips,
+			FetchMissingModules:  fetchMissingModules,
+			CheckReplacedModules: checkReplacedModules,
+			LicenceDetector:      licenceDetector,
+		})
+	} else if checkVendoredModules {
+		if len(args) > 0 {
+			logAndExit("--check-vendored-modules and positional args cannot be set at the same time (received %d)", len(args))
+		}
+
+		vendoredModules = &resolver.VendoredModules{VendorDir: "vendor"}
+		resolvers.Register(vendoredModules)
+	} else if checkBinary != "" {
+		if len(args) > 0 {
+			logAndExit("--check-binary and positional args cannot be set at the same time (received %d)", len(args))
+		}
+
+		resolvers.Register(&resolver.BinaryModules{Binary: checkBinary, FetchMissingModules: fetchMissingModules})
+	} else if checkDepProjects {
+		if len(args) > 0 {
+			logAndExit("--check-dep-projects and positional args cannot be set at the same time (received %d)", len(args))
+		}
+
+		resolvers.Register(&resolver.DepProjects{LockFile: dep.LockFile, VendorDir: "vendor"})
+	} else if vendorDir != "" {
+		if len(args) > 0 {
+			logAndExit("--vendor-dir and positional args cannot be set at the same time (received %d)", len(args))
+		}
+
+		vendorDirProjects = &resolver.VendorDirProjects{VendorDir: vendorDir}
+		resolvers.Register(vendorDirProjects)
+	} else if len(args) > 0 {
+		// positional args are directories, so overridden go modules must be mapped to their directory
+		for module, licence := range overriddenModuleLicences {
+			dir, err := gomodules.Dir(module)
+			if err != nil {
+				logAndExit("Failed to find directory for go module %s: %s", module, err)
+			}
+			config.OverriddenProjectLicences[dir] = licence
 		}
-		log.Info("Found go modules:", args)
-	} else {
-		if len(args) == 0 {
-			logAndExit("requires at least 1 arg (received %d)", len(args))
+		config.OverriddenModuleLicences = nil
+
+		resolvers.Register(&resolver.Directories{Paths: args})
+	}
+
+	if checkNpmPackages != "" {
+		resolvers.Register(&resolver.NpmPackages{ProjectDir: checkNpmPackages})
+	}
+	if checkPythonPackages != "" {
+		resolvers.Register(&resolver.PythonPackages{Dir: checkPythonPackages})
+	}
+	if checkMavenDependencies != "" {
+		resolvers.Register(&resolver.MavenDependencies{DependenciesPath: checkMavenDependencies, Repository: mavenRepository})
+	}
+	if checkCargoCrates != "" {
+		resolvers.Register(&resolver.CargoCrates{LockPath: checkCargoCrates})
+	}
+	if checkOSPackages != "" {
+		resolvers.Register(&resolver.OSPackages{RootFS: checkOSPackages})
+	}
+	for _, pluginConfig := range checkPolicy.Resolvers() {
+		resolvers.Register(&plugin.Resolver{Config: pluginConfig})
+	}
+
+	projects, err := resolvers.Resolve()
+	if err != nil {
+		logAndExit("Failed to resolve projects: %s", err)
+	}
+
+	if checkNestedLicences {
+		projects = withNestedProjects(projects)
+	}
+
+	if checkPolicy.Detection != nil || len(checkPolicy.Detectors()) > 0 || detectSPDXHeaders {
+		detectorNames := checkPolicy.DetectorNames()
+		if detectSPDXHeaders {
+			// SPDX headers are only detected for projects without licence files, before the detector plugins
+			detectorNames = append([]string{detection.DetectorFiles, detection.DetectorSPDXHeaders}, detectorNames[1:]...)
 		}
+		licenceDetector = chainDetector(checkPolicy, detectorNames, licenceDetector, projects)
+	}
+	if deepScan {
+		licenceDetector = detection.NewInventoryDetector(licenceDetector)
+	}
+	if extractCopyrights {
+		licenceDetector = detection.NewCopyrightDetector(licenceDetector)
 	}
 
 	log.Infof("Validating licence compliance with config: %v", config)
-	c := compliance.New(&config, detection.NewLicenceDetector())
-	result, err := c.Validate(args)
+	c := compliance.New(&config, licenceDetector)
+	result, err := c.ValidateProjects(projects)
 	if err != nil {
 		logAndExit("Error validating licence compliance: %v", err)
 	}
+	if licenceList != nil {
+		result.LicenceListVersion = licenceList.Version
+	}
+	if vendoredModules != nil {
+		result.UnlistedVendorDirectories = vendoredModules.UnlistedDirs
+	}
+	if vendorDirProjects != nil {
+		result.OrphanedVendorDirectories = vendorDirProjects.OrphanedDirs
+	}
 	log.Debugf("Licence compliance results: %v", result)
 
-	if len(result.Restricted) > 0 || len(result.Unidentifiable) > 0 {
+	if len(result.UnlistedVendorDirectories) > 0 {
+		log.Warnf("Some vendor directories do not belong to any module of %s: %v", gomodules.ModulesTxt, result.UnlistedVendorDirectories)
+	}
+
+	if len(result.OrphanedVendorDirectories) > 0 {
+		log.Warnf("Some vendor directories have neither a licence nor a known repository root: %v", result.OrphanedVendorDirectories)
+	}
+
+	if len(result.ReplacedLicenceChanged) > 0 {
+		log.Warnf("Some replaced go modules have a different licence than their replacement: %v", result.ReplacedLicenceChanged)
+	}
+
+	if warnings := result.Violations(compliance.SeverityWarning); len(warnings) > 0 {
+		log.Warnf("Some licences are not compliant and/or cannot be identified, with a warning severity: %v", warnings)
+	}
+
+	if result.Failed() {
 		if showComplianceErrors || showComplianceAll {
 			printAsJSON(result)
 		}
-		logAndExit("Some licences are not compliant and/or cannot be identified: restricted: %v, unidentifiable: %v", result.Restricted, result.Unidentifiable)
+		logAndExit("Some licences are not compliant and/or cannot be identified: restricted: %v, unidentifiable: %v, unresolved: %v, mismatched: %v", result.Restricted, result.Unidentifiable, result.Unresolved, result.Mismatched)
 	}
 
 	if showComplianceAll {
@@ -118,28 +354,95 @@ func validateCompliance(_ *cobra.Command, args []string) {
 	log.Info("Licences are compliant")
 }
 
-func getGoModules() ([]string, error) {
-	cmd := exec.Command("go", "list", "-m", "-f", "\"{{.Dir}}\"", "all")
-	var out bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &stderr
+func importLicenceList(cmd *cobra.Command, args []string) {
+	setLogLevel(logLevel)
+
+	list, err := licencelist.Import(args[0], licenceListCacheDir())
+	if err != nil {
+		logAndExit("Failed to import the licence list: %s", err)
+	}
+	fmt.Printf("Imported SPDX licence list %s released %s, with %d licences unknown to the embedded licence database\n", list.Version, list.ReleaseDate, len(list.Licences))
+}
+
+func showLicenceListVersion(cmd *cobra.Command, args []string) {
+	setLogLevel(logLevel)
+
+	list, err := licencelist.Load(licenceListCacheDir())
+	if err != nil {
+		logAndExit("Failed to load the licence list: %s", err)
+	}
+	if list == nil {
+		fmt.Println("No SPDX licence list imported, only the embedded licence database is used")
+		return
+	}
+	fmt.Printf("SPDX licence list %s released %s, with %d licences unknown to the embedded licence database\n", list.Version, list.ReleaseDate, len(list.Licences))
+}
 
-	err := cmd.Run()
+// licenceListCacheDir returns the directory of the licence list cache, from --licence-list-cache or the user cache
+// directory
+func licenceListCacheDir() string {
+	if licenceListCache != "" {
+		return licenceListCache
+	}
+	cacheDir, err := licencelist.CacheDir()
 	if err != nil {
-		logAndExit("Failed to list go modules: %s %s (try setting GO111MODULE=on)", stderr.String(), err)
+		logAndExit("Failed to find the licence list cache: %s", err)
 	}
+	return cacheDir
+}
 
-	modulePaths := strings.Split(out.String(), "\n")
+// chainDetector creates the chain of the given detectors, with the strategy of the policy. The files detector is the
+// given licence detector, and the declared detector uses the declared licences of the given projects, by the path their
+// licence is detected from.
+func chainDetector(checkPolicy *policy.Policy, detectorNames []string, filesDetector detection.LicenceDetector, projects []detection.Result) detection.LicenceDetector {
+	pluginConfigs := make(map[string]plugin.Config)
+	for _, pluginConfig := range checkPolicy.Detectors() {
+		pluginConfigs[pluginConfig.Name] = pluginConfig
+	}
 
-	var r []string
-	for _, str := range modulePaths {
-		str = strings.Trim(strings.TrimSpace(str), "\"")
-		if str != "" {
-			r = append(r, str)
+	var detectors []detection.NamedDetector
+	for _, name := range detectorNames {
+		switch name {
+		case detection.DetectorFiles:
+			detectors = append(detectors, detection.NamedDetector{Name: name, Detector: filesDetector})
+		case detection.DetectorSPDXHeaders:
+			detectors = append(detectors, detection.NamedDetector{Name: name, Detector: detection.NewSPDXHeaderDetector()})
+		case detection.DetectorDeclared:
+			declaredLicences := make(map[string]string)
+			for _, project := range projects {
+				projectPath := project.Directory
+				if projectPath == "" {
+					projectPath = project.Project
+				}
+				declaredLicences[projectPath] = project.DeclaredLicence
+			}
+			detectors = append(detectors, detection.NamedDetector{Name: name, Detector: detection.NewDeclaredDetector(declaredLicences)})
+		default:
+			detectors = append(detectors, detection.NamedDetector{Name: name, Detector: plugin.NewDetector(pluginConfigs[name])})
+		}
+	}
+	return detection.NewChainDetector(checkPolicy.DetectionStrategy(), detectors...)
+}
+
+func withNestedProjects(projects []detection.Result) []detection.Result {
+	var allProjects []detection.Result
+	for _, project := range projects {
+		allProjects = append(allProjects, project)
+		if project.ErrStr != "" || project.IsOSPackage() {
+			continue
+		}
+
+		nestedProjects, err := detection.NestedProjects(project)
+		if err != nil {
+			log.Warnf("Unable to find nested licences of project %s: %v", project.Project, err)
+			continue
+		}
+		if len(nestedProjects) > 0 {
+			log.Infof("Found sub-components with their own licence in project %s: %v", project.Project, nestedProjects)
 		}
+		allProjects = append(allProjects, nestedProjects...)
 	}
-	return r, nil
+	return allProjects
 }
 
 func printAsJSON(results *compliance.Results) {
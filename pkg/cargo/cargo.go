@@ -0,0 +1,133 @@
+package cargo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LockFile is the name of the file locking the crates of a cargo project
+const LockFile = "Cargo.lock"
+
+// Manifest is the name of the file describing a crate
+const Manifest = "Cargo.toml"
+
+// registrySourcePrefix is the prefix of the source of crates downloaded from a registry, e.g. crates.io
+const registrySourcePrefix = "registry+"
+
+// Crate is the description of a crate locked in Cargo.lock
+type Crate struct {
+	Name    string
+	Version string
+	// Source is where the crate comes from, e.g. registry+https://github.com/rust-lang/crates.io-index.
+	// Crates without source are the crates of the project itself.
+	Source string
+	// Dependencies are the crates the crate depends on, as `name`, `name version` or `name version (source)`
+	Dependencies []string
+}
+
+// String returns the identity of the crate, i.e. its name and version
+func (c *Crate) String() string {
+	return c.Name + "@" + c.Version
+}
+
+// IsLocal returns true for the crates of the project itself, i.e. its workspace members and path dependencies
+func (c *Crate) IsLocal() bool {
+	return c.Source == ""
+}
+
+// Lock is the content of a Cargo.lock file
+type Lock struct {
+	Crates []Crate
+}
+
+// IsDirect returns true when the crate is a dependency of one of the crates of the project itself
+func (l *Lock) IsDirect(crate Crate) bool {
+	for _, local := range l.Crates {
+		if !local.IsLocal() {
+			continue
+		}
+		for _, dependency := range local.Dependencies {
+			fields := strings.Fields(dependency)
+			if len(fields) > 0 && fields[0] == crate.Name && (len(fields) == 1 || fields[1] == crate.Version) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadLock reads the crates locked in the given Cargo.lock file
+func ReadLock(path string) (*Lock, error) {
+	tables, err := readTables(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &Lock{}
+	for _, table := range tables {
+		if table.name != "package" {
+			continue
+		}
+		crate := Crate{Name: table.values["name"], Version: table.values["version"], Source: table.values["source"], Dependencies: table.arrays["dependencies"]}
+		if crate.Name == "" || crate.Version == "" {
+			return nil, fmt.Errorf("%s: package without name or version", LockFile)
+		}
+		lock.Crates = append(lock.Crates, crate)
+	}
+	sort.Slice(lock.Crates, func(i, j int) bool {
+		if lock.Crates[i].Name == lock.Crates[j].Name {
+			return lock.Crates[i].Version < lock.Crates[j].Version
+		}
+		return lock.Crates[i].Name < lock.Crates[j].Name
+	})
+	return lock, nil
+}
+
+// Home returns the cargo home directory, i.e. CARGO_HOME or ~/.cargo
+func Home() (string, error) {
+	if cargoHome := os.Getenv("CARGO_HOME"); cargoHome != "" {
+		return cargoHome, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cargo"), nil
+}
+
+// SourceDir returns the directory where the sources of the given registry crate are extracted in the cargo home,
+// i.e. `registry/src/<registry>/<name>-<version>`
+func SourceDir(cargoHome string, crate Crate) (string, error) {
+	if !strings.HasPrefix(crate.Source, registrySourcePrefix) {
+		return "", fmt.Errorf("crate %s from %s is not from a registry", crate.String(), crate.Source)
+	}
+
+	dirs, err := filepath.Glob(filepath.Join(cargoHome, "registry", "src", "*", crate.Name+"-"+crate.Version))
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("crate %s not found in cargo registry cache %s", crate.String(), filepath.Join(cargoHome, "registry", "src"))
+}
+
+// ManifestLicence returns the `license` SPDX expression and the `license-file` of the `[package]` of the Cargo.toml of
+// the given crate directory
+func ManifestLicence(crateDir string) (string, string, error) {
+	tables, err := readTables(filepath.Join(crateDir, Manifest))
+	if err != nil {
+		return "", "", err
+	}
+	for _, table := range tables {
+		if table.name == "package" {
+			return table.values["license"], table.values["license-file"], nil
+		}
+	}
+	return "", "", fmt.Errorf("%s: missing [package]", filepath.Join(crateDir, Manifest))
+}
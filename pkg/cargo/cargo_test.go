@@ -0,0 +1,112 @@
+package cargo
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestCargo(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/cargo.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Cargo Suite", []Reporter{junitReporter})
+}
+
+const cargoHome = "testdata/cargo-home"
+
+var registrySrc = filepath.Join("testdata", "cargo-home", "registry", "src", "index.crates.io-6f17d22bba15001f")
+
+var _ = Describe("cargo crates", func() {
+
+	It("should read the crates locked in Cargo.lock", func() {
+		// when
+		lock, err := ReadLock("testdata/project/Cargo.lock")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lock.Crates).To(HaveLen(5))
+		Expect(lock.Crates[0]).To(Equal(Crate{Name: "app", Version: "0.1.0", Dependencies: []string{"memchr 2.6.3", "serde"}}))
+		Expect(lock.Crates[3]).To(Equal(Crate{
+			Name:         "serde",
+			Version:      "1.0.188",
+			Source:       "registry+https://github.com/rust-lang/crates.io-index",
+			Dependencies: []string{"serde_derive"},
+		}))
+		Expect(lock.Crates[0].IsLocal()).To(BeTrue())
+		Expect(lock.Crates[3].IsLocal()).To(BeFalse())
+	})
+
+	It("should find the crates the project depends on directly", func() {
+		// when
+		lock, err := ReadLock("testdata/project/Cargo.lock")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lock.IsDirect(lock.Crates[1])).To(BeTrue())
+		Expect(lock.IsDirect(lock.Crates[2])).To(BeFalse())
+		Expect(lock.IsDirect(lock.Crates[3])).To(BeTrue())
+		Expect(lock.IsDirect(lock.Crates[4])).To(BeFalse())
+	})
+
+	It("should ignore the empty dependencies of local crates", func() {
+		// given
+		lock := &Lock{Crates: []Crate{{Name: "project", Version: "0.1.0", Dependencies: []string{""}}}}
+
+		// then
+		Expect(lock.IsDirect(Crate{Name: "serde", Version: "1.0.188", Source: "registry+https://github.com/rust-lang/crates.io-index"})).To(BeFalse())
+	})
+
+	It("should find the sources of registry crates in the cargo home", func() {
+		// given
+		lock, err := ReadLock("testdata/project/Cargo.lock")
+		Expect(err).ToNot(HaveOccurred())
+
+		// when
+		serdeDir, err := SourceDir(cargoHome, lock.Crates[3])
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(serdeDir).To(Equal(filepath.Join(registrySrc, "serde-1.0.188")))
+
+		_, err = SourceDir(cargoHome, lock.Crates[2])
+		Expect(err).To(MatchError(ContainSubstring("crate memchr@2.7.1 not found in cargo registry cache")))
+		_, err = SourceDir(cargoHome, lock.Crates[4])
+		Expect(err).To(MatchError(ContainSubstring("is not from a registry")))
+	})
+
+	It("should read the licence of the crate manifest", func() {
+		Expect(ManifestLicence(filepath.Join(registrySrc, "serde-1.0.188"))).To(Equal("MIT OR Apache-2.0"))
+
+		licence, licenceFile, err := ManifestLicence(filepath.Join(registrySrc, "memchr-2.6.3"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(licence).To(BeEmpty())
+		Expect(licenceFile).To(Equal("licenses/COPYING"))
+	})
+
+	It("should use CARGO_HOME as cargo home", func() {
+		// given
+		previousCargoHome, wasSet := os.LookupEnv("CARGO_HOME")
+		defer func() {
+			if wasSet {
+				os.Setenv("CARGO_HOME", previousCargoHome)
+			} else {
+				os.Unsetenv("CARGO_HOME")
+			}
+		}()
+		os.Setenv("CARGO_HOME", cargoHome)
+
+		// then
+		Expect(Home()).To(Equal(cargoHome))
+	})
+})
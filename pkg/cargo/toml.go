@@ -0,0 +1,75 @@
+package cargo
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/sky-uk/licence-compliance-checker/pkg/toml"
+	"os"
+	"strings"
+)
+
+// table is a TOML table with its string and array of strings values
+type table struct {
+	name   string
+	values map[string]string
+	arrays map[string][]string
+}
+
+// readTables reads the tables of the given TOML file, as written by cargo for Cargo.lock and for the normalised
+// Cargo.toml of the crates it downloads. Only the string and array of strings values of tables are read, any other
+// value, e.g. a multi-line string or an inline table, is skipped.
+func readTables(path string) ([]table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tables := []table{{values: make(map[string]string), arrays: make(map[string][]string)}}
+	current := &tables[0]
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "["):
+			name := strings.TrimSpace(strings.Trim(line, "[]"))
+			tables = append(tables, table{name: name, values: make(map[string]string), arrays: make(map[string][]string)})
+			current = &tables[len(tables)-1]
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s:%d: invalid line %q", path, lineNumber, line)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+		switch {
+		case strings.HasPrefix(value, `"""`) || strings.HasPrefix(value, "'''"):
+			// multi-line strings, e.g. descriptions, are skipped up to their closing delimiter
+			delimiter, rest := value[:3], value[3:]
+			for !strings.Contains(rest, delimiter) {
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("%s:%d: unterminated multi-line string", path, lineNumber)
+				}
+				lineNumber++
+				rest = scanner.Text()
+			}
+		case strings.HasPrefix(value, "["):
+			for !strings.HasSuffix(value, "]") && scanner.Scan() {
+				lineNumber++
+				value += strings.TrimSpace(scanner.Text())
+			}
+			if elements, err := toml.ParseStrings(value); err == nil {
+				current.arrays[key] = elements
+			}
+		case strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'"):
+			if s, err := toml.ParseString(value); err == nil {
+				current.values[key] = s
+			}
+		}
+	}
+	return tables, scanner.Err()
+}
@@ -1,6 +1,7 @@
 package compliance
 
 import (
+	"fmt"
 	log "github.com/sirupsen/logrus"
 	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
 	"sort"
@@ -11,6 +12,35 @@ type Config struct {
 	IgnoredProjects           []string
 	RestrictedLicences        []string
 	OverriddenProjectLicences map[string]string
+	OverriddenModuleLicences  map[string]string
+	// DirectSeverity and IndirectSeverity are the severities of the violations of direct and indirect dependencies.
+	// They default to SeverityError, which is also the severity of the violations of projects whose dependency type is
+	// unknown.
+	DirectSeverity   Severity
+	IndirectSeverity Severity
+	// MismatchSeverity is the severity of the projects whose declared licence differs from their detected licence.
+	// It defaults to SeverityWarning.
+	MismatchSeverity Severity
+}
+
+// Severity is how a violation, i.e. a restricted, unidentifiable, unresolved or mismatched project, affects the
+// compliance check
+type Severity string
+
+const (
+	// SeverityError violations fail the compliance check
+	SeverityError Severity = "error"
+	// SeverityWarning violations are reported without failing the compliance check
+	SeverityWarning Severity = "warning"
+)
+
+// ParseSeverity returns the severity of the given name, i.e. error or warning
+func ParseSeverity(name string) (Severity, error) {
+	switch severity := Severity(name); severity {
+	case SeverityError, SeverityWarning:
+		return severity, nil
+	}
+	return "", fmt.Errorf("invalid severity %q, should be one of: %s, %s", name, SeverityError, SeverityWarning)
 }
 
 // Compliance exposes method to validate the licences compliance
@@ -25,6 +55,44 @@ type Results struct {
 	Restricted     []detection.Result `json:"restricted"`
 	Unidentifiable []detection.Result `json:"unidentifiable"`
 	Ignored        []detection.Result `json:"ignored"`
+	// Unresolved lists the projects whose sources could not be found, so their licence could not be detected
+	Unresolved []detection.Result `json:"unresolved,omitempty"`
+	// ReplacedLicenceChanged lists the projects replaced by a module or directory with a different licence than the original module
+	ReplacedLicenceChanged []detection.Result `json:"replacedLicenceChanged,omitempty"`
+	// UnlistedVendorDirectories lists the vendor directories not belonging to any module of `vendor/modules.txt`
+	UnlistedVendorDirectories []string `json:"unlistedVendorDirectories,omitempty"`
+	// OrphanedVendorDirectories lists the vendor directories with neither a licence nor a known repository root
+	OrphanedVendorDirectories []string `json:"orphanedVendorDirectories,omitempty"`
+	// DetectorsDisagree lists the projects whose detectors identify different licences
+	DetectorsDisagree []detection.Result `json:"detectorsDisagree,omitempty"`
+	// Mismatched lists the projects whose declared licence differs from their detected licence
+	Mismatched []detection.Result `json:"mismatched,omitempty"`
+	// LicenceListVersion is the version of the imported SPDX licence list whose licences are detected along with the
+	// embedded licence database
+	LicenceListVersion string `json:"licenceListVersion,omitempty"`
+}
+
+// Failed returns true when some violations have the error severity
+func (r *Results) Failed() bool {
+	return len(r.Violations(SeverityError)) > 0
+}
+
+// Violations returns the restricted, unidentifiable, unresolved and mismatched projects with the given severity.
+// Projects which are neither direct nor indirect dependencies have the error severity.
+func (r *Results) Violations(severity Severity) []detection.Result {
+	var violations []detection.Result
+	for _, results := range [][]detection.Result{r.Restricted, r.Unidentifiable, r.Unresolved, r.Mismatched} {
+		for _, result := range results {
+			resultSeverity := Severity(result.Severity)
+			if resultSeverity == "" {
+				resultSeverity = SeverityError
+			}
+			if resultSeverity == severity {
+				violations = append(violations, result)
+			}
+		}
+	}
+	return violations
 }
 
 // New creates a new compliance checker
@@ -34,15 +102,89 @@ func New(config *Config, licenceDetector detection.LicenceDetector) *Compliance
 
 // Validate performs the licence compliance checks against the given project paths
 func (c *Compliance) Validate(projectPaths []string) (*Results, error) {
-	var complianceResults Results
 	detectionResults, err := c.licenceDetector.Detect(projectPaths)
 	if err != nil {
 		return nil, err
 	}
+	return c.validateResults(detectionResults), nil
+}
 
-	sort.Slice(detectionResults, func(i, j int) bool {
-		return detectionResults[i].Project < detectionResults[j].Project
-	})
+// ValidateProjects performs the licence compliance checks against the given projects, running the licence detection on
+// their LicenceDir, i.e. the directory of their licence file, or their Directory, or their Project when no directory is
+// set. Projects sharing a licence directory are detected once. The other details of the projects, e.g. their go module,
+// are kept in the results. Projects which already have an error could not be resolved, and are reported as unresolved.
+// OS packages have no sources to detect their licence from, and are only checked against their declared licence.
+func (c *Compliance) ValidateProjects(projects []detection.Result) (*Results, error) {
+	var projectPaths []string
+	var unresolvedProjects, undetectableProjects []detection.Result
+	// projects sharing a licence directory, e.g. the packages of a monorepo, are detected once
+	projectsByPath := make(map[string][]detection.Result)
+	for _, project := range projects {
+		if project.ErrStr != "" {
+			unresolvedProjects = append(unresolvedProjects, project)
+			continue
+		}
+		if project.IsOSPackage() {
+			if project.DeclaredLicence == "" {
+				project.ErrStr = "no licence declared, and no sources to detect the licence from"
+			} else {
+				project.ErrStr = "no sources to detect the licence from"
+			}
+			undetectableProjects = append(undetectableProjects, project)
+			continue
+		}
+
+		projectPath := project.LicenceDir()
+		if _, ok := projectsByPath[projectPath]; !ok {
+			projectPaths = append(projectPaths, projectPath)
+		}
+		projectsByPath[projectPath] = append(projectsByPath[projectPath], project)
+	}
+
+	detectionResults, err := c.licenceDetector.Detect(projectPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectResults []detection.Result
+	for _, detectionResult := range detectionResults {
+		pathProjects, ok := projectsByPath[detectionResult.Project]
+		if !ok {
+			projectResults = append(projectResults, detectionResult)
+			continue
+		}
+		for _, project := range pathProjects {
+			project.Matches = detectionResult.Matches
+			project.ErrStr = detectionResult.ErrStr
+			project.Disagreement = detectionResult.Disagreement
+			project.SPDXHeaders = detectionResult.SPDXHeaders
+			project.DifferingFiles = detectionResult.DifferingFiles
+			project.Inventory = detectionResult.Inventory
+			project.Copyrights = detectionResult.Copyrights
+			projectResults = append(projectResults, project)
+		}
+	}
+	detectionResults = append(projectResults, undetectableProjects...)
+
+	complianceResults := c.validateResults(detectionResults)
+	for _, project := range unresolvedProjects {
+		if c.projectIgnored(project) {
+			complianceResults.Ignored = append(complianceResults.Ignored, project)
+		} else {
+			log.Infof("Project '%s' cannot be resolved: %s", project.Project, project.ErrStr)
+			project.Severity = c.severity(project)
+			complianceResults.Unresolved = append(complianceResults.Unresolved, project)
+		}
+	}
+	sortByProject(complianceResults.Ignored)
+	sortByProject(complianceResults.Unresolved)
+	return complianceResults, nil
+}
+
+func (c *Compliance) validateResults(detectionResults []detection.Result) *Results {
+	var complianceResults Results
+
+	sortByProject(detectionResults)
 
 	for _, detectionResult := range detectionResults {
 		c.sortMatchesByConfidenceThenLicence(detectionResult.Matches)
@@ -52,47 +194,158 @@ func (c *Compliance) Validate(projectPaths []string) (*Results, error) {
 			continue
 		}
 
-		if licenceOverride, ok := c.config.OverriddenProjectLicences[detectionResult.Project]; ok {
-			detectionResult = detection.Result{
-				Project: detectionResult.Project,
-				Matches: []detection.LicenceMatch{{Licence: licenceOverride, Confidence: 0}},
+		if len(detectionResult.DifferingFiles) > 0 {
+			log.Warnf("Project '%s' has %d files whose licence differs from the project licence: %v", detectionResult.Project, len(detectionResult.DifferingFiles), detectionResult.DifferingFiles)
+		}
+
+		if len(detectionResult.Disagreement) > 0 {
+			log.Warnf("Project '%s' detectors disagree on its licence: %v", detectionResult.Project, detectionResult.Disagreement)
+			complianceResults.DetectorsDisagree = append(complianceResults.DetectorsDisagree, detectionResult)
+		}
+
+		if c.licenceMismatched(detectionResult) {
+			mismatch := detectionResult
+			mismatch.Severity = string(c.mismatchSeverity())
+			complianceResults.Mismatched = append(complianceResults.Mismatched, mismatch)
+		}
+
+		if detectionResult.ErrStr != "" && detectionResult.DeclaredLicence != "" && len(detectionResult.Disagreement) == 0 {
+			if detection.IsSPDXExpression(detectionResult.DeclaredLicence) {
+				log.Infof("Project '%s' licence cannot be detected (%s), using its declared licence '%s'", detectionResult.Project, detectionResult.ErrStr, detectionResult.DeclaredLicence)
+				detectionResult.Matches = []detection.LicenceMatch{{Licence: detectionResult.DeclaredLicence, Confidence: 0, Detector: detection.DetectorDeclared}}
+				detectionResult.ErrStr = ""
+			} else {
+				log.Infof("Project '%s' licence cannot be detected (%s), and its declared licence '%s' is not an SPDX licence expression", detectionResult.Project, detectionResult.ErrStr, detectionResult.DeclaredLicence)
 			}
 		}
 
+		if licenceOverride, ok := c.licenceOverride(detectionResult); ok {
+			detectionResult.Matches = []detection.LicenceMatch{{Licence: licenceOverride, Confidence: 0}}
+			detectionResult.ErrStr = ""
+		}
+
 		if detectionResult.ErrStr != "" {
+			detectionResult.Severity = c.severity(detectionResult)
 			complianceResults.Unidentifiable = append(complianceResults.Unidentifiable, detectionResult)
 			continue
 		}
 
-		if c.restrictedLicence(detectionResult) {
+		if c.replacedLicenceChanged(detectionResult) {
+			complianceResults.ReplacedLicenceChanged = append(complianceResults.ReplacedLicenceChanged, detectionResult)
+		}
+
+		restrictedFiles := c.restrictedFiles(&detectionResult)
+		if c.restrictedLicence(detectionResult) || restrictedFiles {
+			detectionResult.Severity = c.severity(detectionResult)
 			complianceResults.Restricted = append(complianceResults.Restricted, detectionResult)
 			continue
 		}
 		complianceResults.Compliant = append(complianceResults.Compliant, detectionResult)
 	}
-	return &complianceResults, nil
+	return &complianceResults
 }
 
 func (c *Compliance) restrictedLicence(detectionResult detection.Result) bool {
 	mostProbableLicence := detectionResult.Matches[0].Licence
+	if detection.ExpressionRestricted(mostProbableLicence, c.isRestricted) {
+		log.Infof("Project '%s' most probable license '%s' is restricted", detectionResult.Project, mostProbableLicence)
+		return true
+	}
+	return false
+}
+
+// restrictedFiles marks the files whose licence differs from the project licence and is restricted, returning true when
+// the project has such files
+func (c *Compliance) restrictedFiles(detectionResult *detection.Result) bool {
+	if len(detectionResult.DifferingFiles) == 0 {
+		return false
+	}
+
+	var restricted []string
+	files := append([]detection.FileLicence(nil), detectionResult.DifferingFiles...)
+	for i, file := range files {
+		if detection.ExpressionRestricted(file.Licence, c.isRestricted) {
+			files[i].Restricted = true
+			restricted = append(restricted, file.Path)
+		}
+	}
+	detectionResult.DifferingFiles = files
+	if len(restricted) > 0 {
+		log.Infof("Project '%s' files %v have a restricted licence", detectionResult.Project, restricted)
+		return true
+	}
+	return false
+}
+
+func (c *Compliance) isRestricted(licence string) bool {
 	for _, restrictedLicence := range c.config.RestrictedLicences {
-		if mostProbableLicence == restrictedLicence {
-			log.Infof("Project '%s' most probable license '%s' is restricted", detectionResult.Project, mostProbableLicence)
+		if licence == restrictedLicence {
 			return true
 		}
 	}
 	return false
 }
 
+// severity returns the severity of a violation for the project according to its dependency type. Projects whose
+// dependency type is unknown, e.g. the projects of ecosystems which are not annotated, get the error severity.
+func (c *Compliance) severity(detectionResult detection.Result) string {
+	var severity Severity
+	switch detectionResult.Dependency {
+	case detection.DependencyDirect:
+		severity = c.config.DirectSeverity
+	case detection.DependencyIndirect:
+		severity = c.config.IndirectSeverity
+	}
+	if severity == "" {
+		severity = SeverityError
+	}
+	return string(severity)
+}
+
+func (c *Compliance) replacedLicenceChanged(detectionResult detection.Result) bool {
+	replacement := detectionResult.Replacement
+	if replacement == nil || len(replacement.OriginalMatches) == 0 {
+		return false
+	}
+	c.sortMatchesByConfidenceThenLicence(replacement.OriginalMatches)
+
+	originalLicence := replacement.OriginalMatches[0].Licence
+	mostProbableLicence := detectionResult.Matches[0].Licence
+	if originalLicence != mostProbableLicence {
+		log.Warnf("Project '%s' replacement %s licence '%s' differs from the original module licence '%s'", detectionResult.Project, replacement.Module, mostProbableLicence, originalLicence)
+		return true
+	}
+	return false
+}
+
 func (c *Compliance) projectIgnored(detectionResult detection.Result) bool {
 	for _, ignored := range c.config.IgnoredProjects {
-		if ignored == detectionResult.Project {
+		if ignored == detectionResult.Project || (detectionResult.Module != "" && ignored == detectionResult.Module) ||
+			(detectionResult.Parent != "" && ignored == detectionResult.Parent) {
 			return true
 		}
 	}
 	return false
 }
 
+func (c *Compliance) licenceOverride(detectionResult detection.Result) (string, bool) {
+	if licenceOverride, ok := c.config.OverriddenProjectLicences[detectionResult.Project]; ok {
+		return licenceOverride, true
+	}
+	if detectionResult.Module != "" {
+		if licenceOverride, ok := c.config.OverriddenModuleLicences[detectionResult.Module]; ok {
+			return licenceOverride, true
+		}
+	}
+	return "", false
+}
+
+func sortByProject(detectionResults []detection.Result) {
+	sort.Slice(detectionResults, func(i, j int) bool {
+		return detectionResults[i].Project < detectionResults[j].Project
+	})
+}
+
 func (c *Compliance) sortMatchesByConfidenceThenLicence(matches []detection.LicenceMatch) {
 	sort.Slice(matches, func(i, j int) bool {
 		if matches[i].Confidence == matches[j].Confidence {
@@ -228,6 +228,424 @@ var _ = Describe("compliance check", func() {
 		})
 	})
 
+	Context("when validating projects", func() {
+		It("should keep the projects details in the results", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector(
+				aProjectWithLicence("dir/project1", map[string]float32{"MIT": 0.9}),
+				aProjectWithNoLicence("dir/project2"),
+			)
+			c := New(&Config{RestrictedLicences: []string{"MIT"}}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "dir/project1", Module: "example.com/project1"},
+				{Project: "dir/project2", Module: "example.com/project2", Replacement: &detection.Replacement{Module: "../project2"}},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted).To(HaveProjectLicences("dir/project1", "MIT"))
+			Expect(results.Restricted[0].Module).To(Equal("example.com/project1"))
+			Expect(results.Unidentifiable).To(HaveLen(1))
+			Expect(results.Unidentifiable[0].Module).To(Equal("example.com/project2"))
+			Expect(results.Unidentifiable[0].Replacement.Module).To(Equal("../project2"))
+		})
+
+		It("should detect the licence of projects sharing a licence directory once, and report it for each project", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector(aProjectWithLicence("dir/monorepo", map[string]float32{"GPL-3.0": 0.95}))
+			c := New(&Config{RestrictedLicences: []string{"GPL-3.0"}}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "core@1.0.0", Module: "core", Directory: "dir/monorepo"},
+				{Project: "cli@1.0.0", Module: "cli", Directory: "dir/monorepo"},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(licenceDetector.paths).To(Equal([]string{"dir/monorepo"}))
+			Expect(results.Restricted).To(HaveLen(2))
+			Expect(results.Restricted).To(HaveProjectLicences("core@1.0.0", "GPL-3.0"))
+			Expect(results.Restricted).To(HaveProjectLicences("cli@1.0.0", "GPL-3.0"))
+		})
+
+		It("should match ignored projects and overridden licences by module", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector(
+				aProjectWithLicence("dir/project1", map[string]float32{"MIT": 0.9}),
+				aProjectWithLicence("dir/project2", map[string]float32{"MIT": 0.9}),
+				aProjectWithNoLicence("dir/project3"),
+			)
+			c := New(&Config{
+				RestrictedLicences:       []string{"MIT"},
+				IgnoredProjects:          []string{"example.com/project1"},
+				OverriddenModuleLicences: map[string]string{"example.com/project3": "MIT"},
+			}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "example.com/project1@v1.0.0", Module: "example.com/project1", Version: "v1.0.0", Directory: "dir/project1"},
+				{Project: "example.com/project2@v1.0.0", Module: "example.com/project2", Version: "v1.0.0", Directory: "dir/project2"},
+				{Project: "example.com/project3@v1.0.0", Module: "example.com/project3", Version: "v1.0.0", Directory: "dir/project3"},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Ignored).To(HaveLen(1))
+			Expect(results.Ignored).To(HaveProjectLicences("example.com/project1@v1.0.0", "MIT"))
+			Expect(results.Restricted).To(HaveLen(2))
+			Expect(results.Restricted).To(HaveProjectLicences("example.com/project2@v1.0.0", "MIT"))
+			Expect(results.Restricted).To(HaveProjectLicences("example.com/project3@v1.0.0", "MIT"))
+			Expect(results.Restricted[1].Directory).To(Equal("dir/project3"))
+		})
+
+		It("should only override the licence of sub-components by project", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector(
+				aProjectWithLicence("dir/project", map[string]float32{"BSD-3-Clause": 0.9}),
+				aProjectWithLicence("dir/project/third_party/a", map[string]float32{"BSD-3-Clause": 0.9}),
+				aProjectWithLicence("dir/project/third_party/b", map[string]float32{"BSD-3-Clause": 0.9}),
+			)
+			c := New(&Config{
+				RestrictedLicences:        []string{"BSD-3-Clause"},
+				OverriddenModuleLicences:  map[string]string{"example.com/project": "MIT"},
+				OverriddenProjectLicences: map[string]string{"example.com/project@v1.0.0/third_party/b": "MIT"},
+			}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "example.com/project@v1.0.0", Module: "example.com/project", Version: "v1.0.0", Directory: "dir/project"},
+				{Project: "example.com/project@v1.0.0/third_party/a", Version: "v1.0.0", Directory: "dir/project/third_party/a", Parent: "example.com/project@v1.0.0"},
+				{Project: "example.com/project@v1.0.0/third_party/b", Version: "v1.0.0", Directory: "dir/project/third_party/b", Parent: "example.com/project@v1.0.0"},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Compliant).To(HaveLen(2))
+			Expect(results.Compliant).To(HaveProjectLicences("example.com/project@v1.0.0", "MIT"))
+			Expect(results.Compliant).To(HaveProjectLicences("example.com/project@v1.0.0/third_party/b", "MIT"))
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted).To(HaveProjectLicences("example.com/project@v1.0.0/third_party/a", "BSD-3-Clause"))
+		})
+
+		It("should report projects with an error as unresolved without detecting their licence", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector(
+				aProjectWithLicence("dir/project1", map[string]float32{"MIT": 0.9}),
+			)
+			c := New(&Config{IgnoredProjects: []string{"example.com/project3"}}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "example.com/project1@v1.0.0", Module: "example.com/project1", Directory: "dir/project1"},
+				{Project: "example.com/project2@v1.0.0", Module: "example.com/project2", ErrStr: "module not found"},
+				{Project: "example.com/project3@v1.0.0", Module: "example.com/project3", ErrStr: "module not found"},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Unresolved).To(HaveLen(1))
+			Expect(results.Unresolved).To(HaveNoProjectLicences("example.com/project2@v1.0.0"))
+			Expect(results.Unresolved[0].ErrStr).To(Equal("module not found"))
+			Expect(results.Ignored).To(HaveLen(1))
+			Expect(results.Ignored).To(HaveNoProjectLicences("example.com/project3@v1.0.0"))
+			Expect(results.Unidentifiable).To(BeEmpty())
+		})
+
+		It("should report replaced projects whose licence differs from the original module", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector(
+				aProjectWithLicence("fork1", map[string]float32{"MIT": 0.9}),
+				aProjectWithLicence("fork2", map[string]float32{"MIT": 0.9}),
+				aProjectWithLicence("fork3", map[string]float32{"MIT": 0.9}),
+			)
+			c := New(&Config{}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "fork1", Replacement: &detection.Replacement{Module: "example.com/fork1", Version: "v1.0.0",
+					OriginalMatches: []detection.LicenceMatch{{Licence: "BSD", Confidence: 0.7}, {Licence: "MIT", Confidence: 0.6}}}},
+				{Project: "fork2", Replacement: &detection.Replacement{Module: "example.com/fork2", Version: "v1.0.0",
+					OriginalMatches: []detection.LicenceMatch{{Licence: "MIT", Confidence: 0.9}}}},
+				{Project: "fork3", Replacement: &detection.Replacement{Module: "example.com/fork3", Version: "v1.0.0"}},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Compliant).To(HaveLen(3))
+			Expect(results.ReplacedLicenceChanged).To(HaveLen(1))
+			Expect(results.ReplacedLicenceChanged).To(HaveProjectLicences("fork1", "MIT"))
+		})
+
+		It("should use the declared licence of projects whose licence cannot be detected", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector(
+				aProjectWithNoLicence("dir/declared"),
+				aProjectWithNoLicence("dir/undeclared"),
+				aProjectWithNoLicence("dir/free-text"),
+				aProjectWithNoLicence("dir/expression"),
+			)
+			c := New(&Config{RestrictedLicences: []string{"GPL-3.0"}}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "declared@1.0.0", Directory: "dir/declared", DeclaredLicence: "GPL-3.0"},
+				{Project: "undeclared@1.0.0", Directory: "dir/undeclared"},
+				{Project: "free-text@1.0.0", Directory: "dir/free-text", DeclaredLicence: "The Apache Software License, Version 2.0"},
+				{Project: "expression@1.0.0", Directory: "dir/expression", DeclaredLicence: "GPLv3 OR MIT"},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted).To(HaveProjectLicences("declared@1.0.0", "GPL-3.0"))
+			Expect(results.Restricted[0].ErrStr).To(BeEmpty())
+			Expect(results.Unidentifiable).To(HaveLen(3))
+			Expect(results.Unidentifiable).To(HaveNoProjectLicences("undeclared@1.0.0"))
+			Expect(results.Unidentifiable).To(HaveNoProjectLicences("free-text@1.0.0"))
+			Expect(results.Unidentifiable).To(HaveNoProjectLicences("expression@1.0.0"))
+		})
+
+		It("should report projects whose detectors disagree on their licence", func() {
+			// given
+			disagreeing := aProjectWithLicence("dir/disagreeing", map[string]float32{"MIT": 0.9})
+			disagreeing.Disagreement = []detection.LicenceMatch{
+				{Licence: "MIT", Confidence: 0.9, Detector: "files"},
+				{Licence: "Apache-2.0", Confidence: 0, Detector: "declared"},
+			}
+			licenceDetector := newFakeLicenceDetector(
+				disagreeing,
+				aProjectWithLicence("dir/agreeing", map[string]float32{"MIT": 0.9}),
+			)
+			c := New(&Config{}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "disagreeing@1.0.0", Directory: "dir/disagreeing", DeclaredLicence: "Apache-2.0"},
+				{Project: "agreeing@1.0.0", Directory: "dir/agreeing", DeclaredLicence: "MIT"},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Compliant).To(HaveLen(2))
+			Expect(results.DetectorsDisagree).To(HaveLen(1))
+			Expect(results.DetectorsDisagree).To(HaveProjectLicences("disagreeing@1.0.0", "MIT"))
+			Expect(results.DetectorsDisagree[0].Disagreement).To(HaveLen(2))
+		})
+
+		It("should report projects whose declared licence differs from their detected licence", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector(
+				aProjectWithLicence("dir/gpl", map[string]float32{"GPL-3.0": 0.95, "AGPL-3.0": 0.8}),
+				aProjectWithLicence("dir/mit", map[string]float32{"MIT": 0.95}),
+				aProjectWithLicence("dir/dual", map[string]float32{"Apache-2.0": 0.95}),
+				aProjectWithLicence("dir/deprecated", map[string]float32{"GPL-2.0": 0.95}),
+				aProjectWithLicence("dir/maven", map[string]float32{"Apache-2.0": 0.95}),
+				aProjectWithLicence("dir/overridden", map[string]float32{"GPL-3.0": 0.95}),
+				aProjectWithLicence("dir/bsd", map[string]float32{"BSD-3-Clause": 0.95}),
+				aProjectWithLicence("dir/gplv3", map[string]float32{"MIT": 0.95}),
+			)
+			c := New(&Config{OverriddenProjectLicences: map[string]string{"overridden@1.0.0": "MIT"}}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "gpl@1.0.0", Directory: "dir/gpl", DeclaredLicence: "MIT"},
+				{Project: "mit@1.0.0", Directory: "dir/mit", DeclaredLicence: "mit"},
+				{Project: "dual@1.0.0", Directory: "dir/dual", DeclaredLicence: "(MIT OR Apache-2.0)"},
+				{Project: "deprecated@1.0.0", Directory: "dir/deprecated", DeclaredLicence: "GPL-2.0-only"},
+				{Project: "maven@1.0.0", Directory: "dir/maven", DeclaredLicence: "The Apache Software License, Version 2.0"},
+				{Project: "overridden@1.0.0", Directory: "dir/overridden", DeclaredLicence: "MIT"},
+				{Project: "bsd@1.0.0", Directory: "dir/bsd", DeclaredLicence: "BSD"},
+				{Project: "gplv3@1.0.0", Directory: "dir/gplv3", DeclaredLicence: "GPLv3"},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Compliant).To(HaveLen(8))
+			Expect(results.Mismatched).To(HaveLen(1))
+			Expect(results.Mismatched).To(HaveProjectLicences("gpl@1.0.0", "GPL-3.0", "AGPL-3.0"))
+			Expect(results.Mismatched[0].Severity).To(Equal("warning"))
+			Expect(results.Failed()).To(BeFalse())
+		})
+
+		It("should not compare the licence of sub-components with the declared licence of their parent", func() {
+			// given
+			parent := detection.Result{Project: "nested@1.0.0", Module: "nested", Version: "1.0.0", Directory: "../detection/testdata/nested", DeclaredLicence: "MIT"}
+			subComponents, err := detection.NestedProjects(parent)
+			Expect(err).ToNot(HaveOccurred())
+			licenceDetector := newFakeLicenceDetector(
+				aProjectWithLicence("../detection/testdata/nested", map[string]float32{"MIT": 0.95}),
+				aProjectWithLicence("../detection/testdata/nested/docs/licenses", map[string]float32{"BSD-3-Clause": 0.95}),
+				aProjectWithLicence("../detection/testdata/nested/third_party/lib", map[string]float32{"Apache-2.0": 0.95}),
+			)
+			c := New(&Config{}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects(append([]detection.Result{parent}, subComponents...))
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Compliant).To(HaveLen(3))
+			Expect(results.Compliant).To(HaveProjectLicences("nested@1.0.0/docs/licenses", "BSD-3-Clause"))
+			Expect(results.Compliant).To(HaveProjectLicences("nested@1.0.0/third_party/lib", "Apache-2.0"))
+			Expect(results.Mismatched).To(BeEmpty())
+		})
+
+		It("should fail on mismatched projects with the error severity", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector(aProjectWithLicence("dir/gpl", map[string]float32{"GPL-3.0": 0.95}))
+			c := New(&Config{MismatchSeverity: SeverityError}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{{Project: "gpl@1.0.0", Directory: "dir/gpl", DeclaredLicence: "MIT"}})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Mismatched).To(HaveLen(1))
+			Expect(results.Violations(SeverityError)).To(HaveLen(1))
+			Expect(results.Failed()).To(BeTrue())
+		})
+
+		It("should only check OS packages against their declared licence", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector()
+			c := New(&Config{RestrictedLicences: []string{"GPL-3.0-only"}}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "libc6@2.36", Ecosystem: "deb", Module: "libc6", DeclaredLicence: "(LGPL-2.1-or-later AND GPL-3.0-only)"},
+				{Project: "zlib1g@1.2.13", Ecosystem: "deb", Module: "zlib1g", DeclaredLicence: "Zlib"},
+				{Project: "tzdata@2024a", Ecosystem: "deb", Module: "tzdata"},
+				{Project: "autoconf@2.71", Ecosystem: "deb", Module: "autoconf", DeclaredLicence: "GPL-2+ with Autoconf-exception"},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted).To(HaveProjectLicences("libc6@2.36", "(LGPL-2.1-or-later AND GPL-3.0-only)"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant).To(HaveProjectLicences("zlib1g@1.2.13", "Zlib"))
+			Expect(results.Compliant[0].ErrStr).To(BeEmpty())
+			Expect(results.Unidentifiable).To(HaveLen(2))
+			Expect(results.Unidentifiable).To(HaveNoProjectLicences("autoconf@2.71"))
+			Expect(results.Unidentifiable[0].ErrStr).To(Equal("no sources to detect the licence from"))
+			Expect(results.Unidentifiable).To(HaveNoProjectLicences("tzdata@2024a"))
+			Expect(results.Unidentifiable[1].ErrStr).To(Equal("no licence declared, and no sources to detect the licence from"))
+		})
+
+		It("should apply the severity of the dependency type to violations", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector(
+				aProjectWithLicence("dir/direct", map[string]float32{"MIT": 0.9}),
+				aProjectWithLicence("dir/indirect", map[string]float32{"MIT": 0.9}),
+				aProjectWithNoLicence("dir/unknown"),
+			)
+			c := New(&Config{RestrictedLicences: []string{"MIT"}, IndirectSeverity: SeverityWarning}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "example.com/direct@v1.0.0", Directory: "dir/direct", Dependency: detection.DependencyDirect},
+				{Project: "example.com/indirect@v1.0.0", Directory: "dir/indirect", Dependency: detection.DependencyIndirect},
+				{Project: "example.com/unknown@v1.0.0", Directory: "dir/unknown"},
+				{Project: "example.com/unresolved@v1.0.0", Dependency: detection.DependencyIndirect, ErrStr: "module not found"},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Restricted).To(HaveLen(2))
+			Expect(results.Restricted[0].Severity).To(Equal("error"))
+			Expect(results.Restricted[1].Severity).To(Equal("warning"))
+			Expect(results.Unidentifiable).To(HaveLen(1))
+			Expect(results.Unidentifiable[0].Severity).To(Equal("error"))
+			Expect(results.Unresolved).To(HaveLen(1))
+			Expect(results.Unresolved[0].Severity).To(Equal("warning"))
+			Expect(results.Violations(SeverityError)).To(HaveLen(2))
+			Expect(results.Violations(SeverityWarning)).To(HaveLen(2))
+			Expect(results.Failed()).To(BeTrue())
+		})
+
+		It("should not fail when all violations are warnings", func() {
+			// given
+			licenceDetector := newFakeLicenceDetector(
+				aProjectWithLicence("dir/direct", map[string]float32{"MIT": 0.9}),
+			)
+			c := New(&Config{RestrictedLicences: []string{"MIT"}, DirectSeverity: SeverityWarning}, licenceDetector)
+
+			// when
+			results, err := c.ValidateProjects([]detection.Result{
+				{Project: "example.com/direct@v1.0.0", Directory: "dir/direct", Dependency: detection.DependencyDirect},
+			})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Failed()).To(BeFalse())
+		})
+	})
+
+	It("should find projects with files of a restricted licence", func() {
+		// given
+		gplFile := aProjectWithLicence("gpl-file", map[string]float32{"MIT": 0.9})
+		gplFile.DifferingFiles = []detection.FileLicence{
+			{Path: "src/parser.c", Licence: "GPL-2.0-or-later", Evidence: detection.EvidenceNotice},
+			{Path: "web/app.js", Licence: "MPL-2.0", Evidence: detection.EvidenceNotice},
+		}
+		mplFile := aProjectWithLicence("mpl-file", map[string]float32{"MIT": 0.9})
+		mplFile.DifferingFiles = []detection.FileLicence{{Path: "web/app.js", Licence: "MPL-2.0", Evidence: detection.EvidenceNotice}}
+		licenceDetector := newFakeLicenceDetector(gplFile, mplFile)
+		c := New(&Config{RestrictedLicences: []string{"GPL-2.0-or-later"}}, licenceDetector)
+
+		// when
+		results, err := c.Validate([]string{"gpl-file", "mpl-file"})
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results.Restricted).To(HaveLen(1))
+		Expect(results.Restricted).To(HaveProjectLicences("gpl-file", "MIT"))
+		Expect(results.Restricted[0].DifferingFiles).To(Equal([]detection.FileLicence{
+			{Path: "src/parser.c", Licence: "GPL-2.0-or-later", Evidence: detection.EvidenceNotice, Restricted: true},
+			{Path: "web/app.js", Licence: "MPL-2.0", Evidence: detection.EvidenceNotice},
+		}))
+		Expect(results.Compliant).To(HaveLen(1))
+		Expect(results.Compliant).To(HaveProjectLicences("mpl-file", "MIT"))
+	})
+
+	It("should check the licences combined by licence expressions", func() {
+		// given
+		licenceDetector := newFakeLicenceDetector(
+			aProjectWithLicence("and", map[string]float32{"(MIT AND GPL-3.0)": 0.9}),
+			aProjectWithLicence("or", map[string]float32{"(MIT OR GPL-3.0)": 0.9}),
+			aProjectWithLicence("or-restricted", map[string]float32{"GPL-3.0 OR (AGPL-3.0 AND MIT)": 0.9}),
+			aProjectWithLicence("exception", map[string]float32{"GPL-3.0 WITH GCC-exception-3.1": 0.9}),
+			aProjectWithLicence("invalid", map[string]float32{"MIT and/or GPL-3.0": 0.9}),
+		)
+		c := New(&Config{RestrictedLicences: []string{"GPL-3.0", "AGPL-3.0"}}, licenceDetector)
+
+		// when
+		results, err := c.Validate([]string{"and", "or", "or-restricted", "exception", "invalid"})
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results.Restricted).To(HaveLen(3))
+		Expect(results.Restricted).To(HaveProjectLicences("and", "(MIT AND GPL-3.0)"))
+		Expect(results.Restricted).To(HaveProjectLicences("or-restricted", "GPL-3.0 OR (AGPL-3.0 AND MIT)"))
+		Expect(results.Restricted).To(HaveProjectLicences("exception", "GPL-3.0 WITH GCC-exception-3.1"))
+		Expect(results.Compliant).To(HaveLen(2))
+		Expect(results.Compliant).To(HaveProjectLicences("or", "(MIT OR GPL-3.0)"))
+		Expect(results.Compliant).To(HaveProjectLicences("invalid", "MIT and/or GPL-3.0"))
+	})
+
+	It("should only accept the error and warning severities", func() {
+		Expect(ParseSeverity("warning")).To(Equal(SeverityWarning))
+		Expect(ParseSeverity("error")).To(Equal(SeverityError))
+		_, err := ParseSeverity("fatal")
+		Expect(err).To(HaveOccurred())
+	})
+
 })
 
 func aProjectWithLicence(project string, licencesConfidence map[string]float32) detection.Result {
@@ -244,13 +662,15 @@ func aProjectWithNoLicence(project string) detection.Result {
 
 type FakeLicenceDetector struct {
 	detectionResults []detection.Result
+	paths            []string
 }
 
 func newFakeLicenceDetector(result ...detection.Result) *FakeLicenceDetector {
-	return &FakeLicenceDetector{result}
+	return &FakeLicenceDetector{detectionResults: result}
 }
 
 func (d *FakeLicenceDetector) Detect(paths []string) ([]detection.Result, error) {
+	d.paths = paths
 	return d.detectionResults, nil
 }
 
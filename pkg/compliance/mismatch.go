@@ -0,0 +1,42 @@
+package compliance
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+)
+
+// licenceMismatched returns true when the declared licence of the project shares none of its licences with the most
+// probable licence detected for the project. Declared licences which are not SPDX licence expressions of SPDX
+// licences, e.g. the licence names of maven POMs or free text such as `BSD` or `GPLv3`, cannot be compared and are never
+// mismatched. Projects whose licence is overridden, or
+// only known from their declared licence, are not compared either.
+func (c *Compliance) licenceMismatched(detectionResult detection.Result) bool {
+	if detectionResult.DeclaredLicence == "" || detectionResult.ErrStr != "" || len(detectionResult.Matches) == 0 {
+		return false
+	}
+	mostProbable := detectionResult.Matches[0]
+	if mostProbable.Detector == detection.DetectorDeclared {
+		return false
+	}
+	if _, ok := c.licenceOverride(detectionResult); ok {
+		return false
+	}
+
+	if !detection.IsSPDXExpression(detectionResult.DeclaredLicence) {
+		log.Debugf("Project '%s' declared licence '%s' is not an SPDX licence expression, and is not compared with its detected licence", detectionResult.Project, detectionResult.DeclaredLicence)
+		return false
+	}
+	if detection.LicencesAgree(detectionResult.DeclaredLicence, mostProbable.Licence) {
+		return false
+	}
+	log.Warnf("Project '%s' declared licence '%s' differs from its detected licence '%s'", detectionResult.Project, detectionResult.DeclaredLicence, mostProbable.Licence)
+	return true
+}
+
+// mismatchSeverity returns the severity of the projects whose declared licence differs from their detected licence
+func (c *Compliance) mismatchSeverity() Severity {
+	if c.config.MismatchSeverity == "" {
+		return SeverityWarning
+	}
+	return c.config.MismatchSeverity
+}
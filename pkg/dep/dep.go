@@ -0,0 +1,165 @@
+package dep
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/sky-uk/licence-compliance-checker/pkg/toml"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LockFile is the name of the file locking the projects of a dep-managed project, as created by `dep ensure`
+const LockFile = "Gopkg.lock"
+
+// Project is the description of a project locked in Gopkg.lock
+type Project struct {
+	Name     string
+	Source   string
+	Version  string
+	Branch   string
+	Revision string
+	Packages []string
+}
+
+// Lock is the content of a Gopkg.lock file relevant to the licence compliance check
+type Lock struct {
+	Projects []Project
+	// InputImports are the packages imported by the dep-managed project itself
+	InputImports []string
+}
+
+// String returns the identity of the project, i.e. its name and version, or revision when it has no version
+func (p *Project) String() string {
+	if version := p.VersionOrRevision(); version != "" {
+		return p.Name + "@" + version
+	}
+	return p.Name
+}
+
+// VersionOrRevision returns the version of the project, or its revision when it is locked to a branch or a revision
+func (p *Project) VersionOrRevision() string {
+	if p.Version != "" {
+		return p.Version
+	}
+	return p.Revision
+}
+
+// Dir returns the directory the project is vendored into, within the given vendor directory
+func (p *Project) Dir(vendorDir string) string {
+	return filepath.Join(vendorDir, filepath.FromSlash(p.Name))
+}
+
+// IsDirect returns true when some of the packages imported by the dep-managed project belong to the given project
+func (l *Lock) IsDirect(project Project) bool {
+	for _, inputImport := range l.InputImports {
+		if inputImport == project.Name || strings.HasPrefix(inputImport, project.Name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadLock reads the projects locked in the given Gopkg.lock file.
+// Gopkg.lock is generated by dep, so only the TOML constructs dep writes are supported: tables, array of tables,
+// and keys with a string, integer or array of strings value.
+func ReadLock(path string) (*Lock, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lock := &Lock{}
+	var project *Project
+	var table string
+	addProject := func() {
+		if project != nil {
+			lock.Projects = append(lock.Projects, *project)
+			project = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[["):
+			addProject()
+			table = strings.TrimSpace(strings.Trim(line, "[]"))
+			if table == "projects" {
+				project = &Project{}
+			}
+			continue
+		case strings.HasPrefix(line, "["):
+			addProject()
+			table = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+
+		key, value, err := parseKeyValue(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", LockFile, lineNumber, err)
+		}
+		if strings.HasPrefix(value, "[") {
+			for !strings.HasSuffix(value, "]") && scanner.Scan() {
+				lineNumber++
+				value += strings.TrimSpace(scanner.Text())
+			}
+		}
+
+		switch {
+		case project != nil:
+			if err := project.set(key, value); err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", LockFile, lineNumber, err)
+			}
+		case table == "solve-meta" && key == "input-imports":
+			if lock.InputImports, err = toml.ParseStrings(value); err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", LockFile, lineNumber, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	addProject()
+
+	for _, p := range lock.Projects {
+		if p.Name == "" {
+			return nil, fmt.Errorf("%s: project without name", LockFile)
+		}
+	}
+	return lock, nil
+}
+
+func (p *Project) set(key, value string) error {
+	var err error
+	switch key {
+	case "name":
+		p.Name, err = toml.ParseString(value)
+	case "source":
+		p.Source, err = toml.ParseString(value)
+	case "version":
+		p.Version, err = toml.ParseString(value)
+	case "branch":
+		p.Branch, err = toml.ParseString(value)
+	case "revision":
+		p.Revision, err = toml.ParseString(value)
+	case "packages":
+		p.Packages, err = toml.ParseStrings(value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s value %s: %v", key, value, err)
+	}
+	return nil
+}
+
+func parseKeyValue(line string) (string, string, error) {
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid line %q", line)
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+}
@@ -0,0 +1,97 @@
+package dep
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestDep(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/dep.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Dep Suite", []Reporter{junitReporter})
+}
+
+var _ = Describe("Gopkg.lock", func() {
+
+	It("should read the locked projects", func() {
+		// when
+		lock, err := ReadLock("testdata/Gopkg.lock")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lock.Projects).To(Equal([]Project{
+			{
+				Name:     "github.com/branch/project",
+				Branch:   "master",
+				Revision: "7fe510aff544695d5ca79be4cddc5dafd0e02fa4",
+				Packages: []string{"."},
+			},
+			{
+				Name:     "github.com/version/project",
+				Source:   "https://github.com/fork/project.git",
+				Version:  "v1.9.0",
+				Revision: "f6c17b524822278a87e3b3bd809fec33b51f5b46",
+				Packages: []string{"lists", "lists/arraylist"},
+			},
+		}))
+		Expect(lock.InputImports).To(Equal([]string{"github.com/version/project/lists"}))
+	})
+
+	It("should identify projects by name and version, or revision without version", func() {
+		// when
+		lock, err := ReadLock("testdata/Gopkg.lock")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lock.Projects[0].String()).To(Equal("github.com/branch/project@7fe510aff544695d5ca79be4cddc5dafd0e02fa4"))
+		Expect(lock.Projects[1].String()).To(Equal("github.com/version/project@v1.9.0"))
+		Expect(lock.Projects[1].Dir("vendor")).To(Equal(filepath.Join("vendor", "github.com", "version", "project")))
+	})
+
+	It("should find the projects imported directly", func() {
+		// when
+		lock, err := ReadLock("testdata/Gopkg.lock")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lock.IsDirect(lock.Projects[0])).To(BeFalse())
+		Expect(lock.IsDirect(lock.Projects[1])).To(BeTrue())
+		Expect(lock.IsDirect(Project{Name: "github.com/version/pro"})).To(BeFalse())
+	})
+
+	It("should fail when the lock file is invalid", func() {
+		// given
+		tmpDir, err := ioutil.TempDir("", "dep")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		lockPath := filepath.Join(tmpDir, LockFile)
+		Expect(ioutil.WriteFile(lockPath, []byte("[[projects]]\n  name = github.com/unquoted\n"), 0644)).To(Succeed())
+
+		// when
+		_, err = ReadLock(lockPath)
+
+		// then
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail when there is no lock file", func() {
+		// when
+		_, err := ReadLock("testdata/does-not-exist/Gopkg.lock")
+
+		// then
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,194 @@
+package detection
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Names of the built-in detectors, recorded in the matches they produce
+const (
+	// DetectorFiles detects licences from the licence files of projects, with go-license-detector
+	DetectorFiles = "files"
+	// DetectorDeclared uses the licences declared by the metadata of projects, e.g. their package.json
+	DetectorDeclared = "declared"
+)
+
+// Strategy is how a chain of detectors combines their results
+type Strategy string
+
+const (
+	// StrategyFirstHit runs all the detectors, and uses the licence of the first detector identifying one
+	StrategyFirstHit Strategy = "first-hit"
+	// StrategyFallback runs each next detector only for the paths whose licence has not been identified yet
+	StrategyFallback Strategy = "fallback"
+	// StrategyConsensus runs all the detectors, and only identifies a licence when all the detectors identifying one agree
+	StrategyConsensus Strategy = "consensus"
+)
+
+// ParseStrategy returns the strategy of the given name, i.e. first-hit, fallback or consensus
+func ParseStrategy(name string) (Strategy, error) {
+	switch strategy := Strategy(name); strategy {
+	case StrategyFirstHit, StrategyFallback, StrategyConsensus:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("invalid detection strategy %q, should be one of: %s, %s, %s", name, StrategyFirstHit, StrategyFallback, StrategyConsensus)
+}
+
+// NamedDetector is a detector of a chain, whose name is recorded in the matches it produces
+type NamedDetector struct {
+	Name     string
+	Detector LicenceDetector
+}
+
+// chainDetector is an implementation of LicenceDetector combining the results of several detectors
+type chainDetector struct {
+	strategy  Strategy
+	detectors []NamedDetector
+}
+
+// NewChainDetector creates a LicenceDetector combining the given detectors, in order, with the given strategy.
+// With the first-hit and consensus strategies, the results of paths whose detectors identify different licences record
+// their disagreement.
+func NewChainDetector(strategy Strategy, detectors ...NamedDetector) LicenceDetector {
+	return &chainDetector{strategy: strategy, detectors: detectors}
+}
+
+// Detect returns a result per path, in the order of the paths. The results of paths whose licence is identified by
+// no detector have the errors of all the detectors.
+func (d *chainDetector) Detect(paths []string) ([]Result, error) {
+	detectorResults := make(map[string][]Result)
+	remainingPaths := paths
+	for _, detector := range d.detectors {
+		if len(remainingPaths) == 0 {
+			break
+		}
+		results, err := detector.Detector.Detect(remainingPaths)
+		if err != nil {
+			return nil, fmt.Errorf("detector %s failed: %v", detector.Name, err)
+		}
+
+		resultsByPath := make(map[string]Result)
+		for _, result := range results {
+			resultsByPath[result.Project] = result
+		}
+		var unidentifiedPaths []string
+		for _, path := range remainingPaths {
+			result, ok := resultsByPath[path]
+			if !ok {
+				result = Result{Project: path, ErrStr: "no result"}
+			}
+			result.Matches = withDetector(result.Matches, detector.Name)
+			if result.ErrStr == "" && len(result.Matches) == 0 {
+				result.ErrStr = "no licence found"
+			}
+			if result.ErrStr != "" {
+				result.ErrStr = detector.Name + ": " + result.ErrStr
+			}
+			detectorResults[path] = append(detectorResults[path], result)
+			if !identified(result) {
+				unidentifiedPaths = append(unidentifiedPaths, path)
+			}
+		}
+		if d.strategy == StrategyFallback {
+			remainingPaths = unidentifiedPaths
+		}
+	}
+
+	var results []Result
+	for _, path := range paths {
+		results = append(results, d.combine(path, detectorResults[path]))
+	}
+	return results, nil
+}
+
+// combine returns the result of a path from the results of the detectors which ran for it, in order. Detectors disagree
+// when the most probable licences of two of them share no licence, once normalised, so that e.g. `MIT OR Apache-2.0`
+// agrees with `mit`.
+func (d *chainDetector) combine(path string, results []Result) Result {
+	var errs []string
+	var identifiedResults []Result
+	for _, result := range results {
+		if identified(result) {
+			identifiedResults = append(identifiedResults, result)
+		} else {
+			errs = append(errs, result.ErrStr)
+		}
+	}
+	if len(identifiedResults) == 0 {
+		return Result{Project: path, ErrStr: strings.Join(errs, "; ")}
+	}
+
+	combined := identifiedResults[0]
+	combined.Project = path
+	var disagreement []LicenceMatch
+	disagree := false
+	for _, result := range identifiedResults {
+		mostProbable := mostProbableMatch(result.Matches)
+		for _, match := range disagreement {
+			if !LicencesAgree(match.Licence, mostProbable.Licence) {
+				disagree = true
+			}
+		}
+		disagreement = append(disagreement, mostProbable)
+	}
+	if disagree {
+		combined.Disagreement = disagreement
+		if d.strategy == StrategyConsensus {
+			var descriptions []string
+			for _, match := range disagreement {
+				descriptions = append(descriptions, match.Detector+": "+match.Licence)
+			}
+			return Result{Project: path, ErrStr: "detectors disagree: " + strings.Join(descriptions, ", "), Disagreement: disagreement}
+		}
+	}
+	return combined
+}
+
+func identified(result Result) bool {
+	return result.ErrStr == "" && len(result.Matches) > 0
+}
+
+func withDetector(matches []LicenceMatch, name string) []LicenceMatch {
+	var named []LicenceMatch
+	for _, match := range matches {
+		match.Detector = name
+		named = append(named, match)
+	}
+	return named
+}
+
+func mostProbableMatch(matches []LicenceMatch) LicenceMatch {
+	sorted := append([]LicenceMatch(nil), matches...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Confidence == sorted[j].Confidence {
+			return sorted[i].Licence < sorted[j].Licence
+		}
+		return sorted[i].Confidence > sorted[j].Confidence
+	})
+	return sorted[0]
+}
+
+// declaredDetector is an implementation of LicenceDetector using the licences declared by the metadata of projects
+type declaredDetector struct {
+	declaredLicences map[string]string
+}
+
+// NewDeclaredDetector creates a LicenceDetector returning the declared licence of each path, given the declared
+// licences by path
+func NewDeclaredDetector(declaredLicences map[string]string) LicenceDetector {
+	return &declaredDetector{declaredLicences: declaredLicences}
+}
+
+// Detect returns the declared licence of each path, or an error for paths without declared licence
+func (d *declaredDetector) Detect(paths []string) ([]Result, error) {
+	var results []Result
+	for _, path := range paths {
+		if licence := d.declaredLicences[path]; licence != "" {
+			results = append(results, Result{Project: path, Matches: []LicenceMatch{{Licence: licence, Confidence: 0, Detector: DetectorDeclared}}})
+		} else {
+			results = append(results, Result{Project: path, ErrStr: "no declared licence"})
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,218 @@
+package detection
+
+import (
+	"bytes"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Copyright is a copyright holder of a project, along with the years of their copyright statements
+type Copyright struct {
+	Holder string `json:"holder"`
+	// Years are the years of the copyright statements of the holder, as ranges of consecutive years, e.g. 2015-2018, 2020
+	Years string `json:"years,omitempty"`
+}
+
+var (
+	// noticeFileRe matches the names of NOTICE files, which hold the attributions of projects
+	noticeFileRe = regexp.MustCompile(`^notices?(|\.md|\.rst|\.html|\.txt)$`)
+	// copyrightStatementRe matches the lines of copyright statements, after their comment markers, capturing the
+	// statement from its copyright marker
+	copyrightStatementRe = regexp.MustCompile(`(?i)^[\s/*#;!%<-]*((?:spdx-filecopyrighttext:|copyright\b|©|\(c\)).*)$`)
+	// copyrightMarkersRe matches the copyright markers at the beginning of statements, e.g. Copyright (c)
+	copyrightMarkersRe = regexp.MustCompile(`(?i)^(?:\s*(?:spdx-filecopyrighttext|copyright\b|©|\(c\)|:))+`)
+	// copyrightYearsRe matches a year or a range of years at the beginning of statements, e.g. 2015-18 or 2015-present
+	copyrightYearsRe = regexp.MustCompile(`(?i)^[\s,;]*((?:19|20)\d{2})\b(?:\s*(?:-|–|to)\s*((?:19|20)\d{2}|\d{2}|present)\b)?`)
+	// holderAddressRe matches the email addresses and URLs following holders, e.g. <jane@example.com>
+	holderAddressRe = regexp.MustCompile(`<[^<>]*[@/][^<>]*>|\(\s*https?://[^)]*\)`)
+	// allRightsReservedRe matches the reservation of rights following holders
+	allRightsReservedRe = regexp.MustCompile(`(?i)[\s.,;]*all rights reserved\.?`)
+	// holderPlaceholderRe matches the placeholders of the copyright statements of licence templates, e.g. <year> or
+	// [name of copyright owner]
+	holderPlaceholderRe = regexp.MustCompile(`(?i)[\[\]{}<>]|^(yyyy|year)\b|name of (the )?(author|copyright owner)`)
+)
+
+// copyrightDetector is an implementation of LicenceDetector adding the copyrights of projects to the results of another
+// detector
+type copyrightDetector struct {
+	detector LicenceDetector
+}
+
+// NewCopyrightDetector creates a LicenceDetector which extracts the copyright statements of projects after running the
+// given detector. Statements are extracted from the licence and NOTICE files of projects, and from the header of their
+// other files. Their holders and years are normalised and de-duplicated into the Copyrights of the results.
+func NewCopyrightDetector(detector LicenceDetector) LicenceDetector {
+	return &copyrightDetector{detector: detector}
+}
+
+// Detect returns the results of the detector, with the copyrights of each project
+func (d *copyrightDetector) Detect(paths []string) ([]Result, error) {
+	results, err := d.detector.Detect(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		copyrights, err := projectCopyrights(results[i].Project)
+		if err != nil {
+			log.Warnf("Unable to extract the copyrights of project '%s': %v", results[i].Project, err)
+			continue
+		}
+		results[i].Copyrights = copyrights
+	}
+	return results, nil
+}
+
+// projectCopyrights returns the copyrights of the files of a project, ordered by holder
+func projectCopyrights(projectPath string) ([]Copyright, error) {
+	f, err := projectFiler(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	holders := make(map[string]*copyrightHolder)
+	err = walkFiles(f, "", func(filePath string) error {
+		content, err := f.ReadFile(filePath)
+		if err != nil {
+			// e.g. dangling symbolic links
+			return nil
+		}
+		name := strings.ToLower(path.Base(filePath))
+		if candidateLicenceFileRe.MatchString(name) || noticeFileRe.MatchString(name) {
+			if bytes.IndexByte(content, 0) >= 0 {
+				return nil
+			}
+		} else if content = fileHeader(content); content == nil {
+			return nil
+		}
+
+		for _, line := range strings.Split(string(content), "\n") {
+			statement, ok := parseCopyright(line)
+			if !ok {
+				continue
+			}
+			key := strings.ToLower(statement.holder)
+			if holders[key] == nil {
+				holders[key] = &copyrightHolder{holder: statement.holder, years: make(map[int]bool)}
+			}
+			for _, year := range statement.years {
+				holders[key].years[year] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var copyrights []Copyright
+	for _, holder := range holders {
+		copyrights = append(copyrights, Copyright{Holder: holder.holder, Years: formatYears(holder.years)})
+	}
+	sort.Slice(copyrights, func(i, j int) bool {
+		return strings.ToLower(copyrights[i].Holder) < strings.ToLower(copyrights[j].Holder)
+	})
+	return copyrights, nil
+}
+
+// copyrightHolder is a holder along with the years of all their copyright statements
+type copyrightHolder struct {
+	holder string
+	years  map[int]bool
+}
+
+// copyrightStatement is a copyright statement, with its holder and each year it covers
+type copyrightStatement struct {
+	holder string
+	years  []int
+}
+
+// parseCopyright returns the copyright statement of a line, which must either have a year or a copyright symbol, so that
+// the mentions of copyrights in licence texts are not taken for statements
+func parseCopyright(line string) (copyrightStatement, bool) {
+	submatches := copyrightStatementRe.FindStringSubmatch(line)
+	if submatches == nil {
+		return copyrightStatement{}, false
+	}
+	statement := submatches[1]
+	markers := copyrightMarkersRe.FindString(statement)
+	lowerMarkers := strings.ToLower(markers)
+	hasSymbol := strings.Contains(markers, "©") || strings.Contains(lowerMarkers, "(c)") || strings.Contains(lowerMarkers, "spdx-filecopyrighttext")
+	statement = statement[len(markers):]
+
+	var years []int
+	for {
+		yearRange := copyrightYearsRe.FindStringSubmatch(statement)
+		if yearRange == nil {
+			break
+		}
+		years = append(years, yearsOf(yearRange[1], yearRange[2])...)
+		statement = statement[len(yearRange[0]):]
+	}
+	if len(years) == 0 && !hasSymbol {
+		return copyrightStatement{}, false
+	}
+
+	holder := statement[len(copyrightMarkersRe.FindString(statement)):]
+	holder = spdxCommentEndRe.ReplaceAllString(holder, "")
+	holder = holderAddressRe.ReplaceAllString(holder, "")
+	holder = allRightsReservedRe.ReplaceAllString(holder, "")
+	holder = strings.Join(strings.Fields(holder), " ")
+	holder = strings.Trim(holder, " .,;:-")
+	holder = strings.TrimPrefix(holder, "by ")
+	if holder == "" || holderPlaceholderRe.MatchString(holder) {
+		return copyrightStatement{}, false
+	}
+	return copyrightStatement{holder: holder, years: years}, true
+}
+
+// yearsOf returns each year of a range of years, whose end may be missing, abbreviated to two digits or present, i.e.
+// the current year
+func yearsOf(start, end string) []int {
+	from, _ := strconv.Atoi(start)
+	to := from
+	if strings.EqualFold(end, "present") {
+		end = strconv.Itoa(time.Now().Year())
+	} else if len(end) == 2 {
+		end = start[:2] + end
+	}
+	if year, err := strconv.Atoi(end); err == nil && year > from {
+		to = year
+	}
+	var years []int
+	for year := from; year <= to; year++ {
+		years = append(years, year)
+	}
+	return years
+}
+
+// formatYears returns the years as ordered ranges of consecutive years, e.g. 2015-2018, 2020
+func formatYears(years map[int]bool) string {
+	var sorted []int
+	for year := range years {
+		sorted = append(sorted, year)
+	}
+	sort.Ints(sorted)
+
+	var ranges []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(sorted[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ", ")
+}
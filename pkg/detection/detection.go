@@ -1,5 +1,7 @@
 package detection
 
+import "path/filepath"
+
 // LicenceDetector defines the behaviour for Licence detectors
 type LicenceDetector interface {
 	Detect(paths []string) ([]Result, error)
@@ -10,15 +12,98 @@ func NewLicenceDetector() LicenceDetector {
 	return &goLicenseDetector{}
 }
 
+// NewLicenceDetectorWithTemplates creates a new LicenceDetector also matching the licence files of projects with the
+// given licence templates, e.g. proprietary licences, with the same confidence scoring as the SPDX licences
+func NewLicenceDetectorWithTemplates(templates []LicenceTemplate) LicenceDetector {
+	return &goLicenseDetector{templates: templates}
+}
+
+// Ecosystems of the projects identified as packages of a package manager
+const (
+	EcosystemGo     = "go"
+	EcosystemNpm    = "npm"
+	EcosystemPython = "python"
+	EcosystemMaven  = "maven"
+	EcosystemCargo  = "cargo"
+	// EcosystemDeb and EcosystemApk are the OS packages of root filesystems
+	EcosystemDeb = "deb"
+	EcosystemApk = "apk"
+)
+
+// Scopes of the projects, i.e. whether they are shipped with the main project or only used for its development
+const (
+	ScopeProd = "prod"
+	ScopeDev  = "dev"
+)
+
+// Dependency types of the projects, as seen from the main project
+const (
+	DependencyDirect   = "direct"
+	DependencyIndirect = "indirect"
+)
+
 // Result is a representation of the Licence detection outcome for a project
 type Result struct {
-	Project string         `json:"project,omitempty"`
-	Matches []LicenceMatch `json:"matches,omitempty"`
-	ErrStr  string         `json:"error,omitempty"`
+	Project         string         `json:"project,omitempty"`
+	Ecosystem       string         `json:"ecosystem,omitempty"`
+	Module          string         `json:"module,omitempty"`
+	Version         string         `json:"version,omitempty"`
+	Directory       string         `json:"directory,omitempty"`
+	Parent          string         `json:"parent,omitempty"`
+	Dependency      string         `json:"dependency,omitempty"`
+	Scope           string         `json:"scope,omitempty"`
+	Replacement     *Replacement   `json:"replacement,omitempty"`
+	DeclaredLicence string         `json:"declaredLicense,omitempty"`
+	Matches         []LicenceMatch `json:"matches,omitempty"`
+	ErrStr          string         `json:"error,omitempty"`
+	Severity        string         `json:"severity,omitempty"`
+	// Disagreement holds the most probable licence of each detector of a chain which identified a licence, when they
+	// do not all identify the same licence
+	Disagreement []LicenceMatch `json:"disagreement,omitempty"`
+	// SPDXHeaders counts the files of the project by the licence expression of their SPDX-License-Identifier header
+	SPDXHeaders []HeaderCount `json:"spdxHeaders,omitempty"`
+	// DifferingFiles are the files of the project whose licence differs from the licence of the project
+	DifferingFiles []FileLicence `json:"differingFiles,omitempty"`
+	// Inventory are the files of the project whose licence is identified by a deep scan of the project
+	Inventory []FileLicence `json:"inventory,omitempty"`
+	// Copyrights are the copyright holders of the project, from the copyright statements of its files
+	Copyrights []Copyright `json:"copyrights,omitempty"`
+	// LicenceFile is the slash separated path of the licence file of the project relative to its directory, when it
+	// is declared by the project, e.g. the `license-file` of a crate
+	LicenceFile string `json:"licenseFile,omitempty"`
+}
+
+// IsOSPackage returns true for the OS packages of root filesystems, which have no sources to detect their licence from
+func (r Result) IsOSPackage() bool {
+	return r.Ecosystem == EcosystemDeb || r.Ecosystem == EcosystemApk
+}
+
+// LicenceDir returns the directory the licence of the project is detected from, i.e. the directory of its licence file
+// when it is declared, or else its directory, or else the project itself
+func (r Result) LicenceDir() string {
+	dir := r.Directory
+	if dir == "" {
+		dir = r.Project
+	}
+	if r.LicenceFile != "" {
+		return filepath.Join(dir, filepath.Dir(filepath.FromSlash(r.LicenceFile)))
+	}
+	return dir
+}
+
+// Replacement describes the module or local directory used in place of a project's go module through a `replace` directive
+type Replacement struct {
+	Module          string         `json:"module"`
+	Version         string         `json:"version,omitempty"`
+	OriginalMatches []LicenceMatch `json:"originalMatches,omitempty"`
 }
 
 // LicenceMatch describes the level of confidence for the detected Licence
 type LicenceMatch struct {
 	Licence    string  `json:"license"`
 	Confidence float32 `json:"confidence"`
+	// Detector is the name of the detector which produced the match, e.g. files
+	Detector string `json:"detector,omitempty"`
+	// Evidence is the files of the project the licence is found in
+	Evidence []Evidence `json:"evidence,omitempty"`
 }
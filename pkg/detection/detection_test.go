@@ -0,0 +1,534 @@
+package detection
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestDetection(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/detection.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Detection Suite", []Reporter{junitReporter})
+}
+
+var _ = Describe("licence detection", func() {
+
+	Context("zip archives", func() {
+		It("should detect the licence of a go module zip nested under its module path and version", func() {
+			// when
+			results, err := NewLicenceDetector().Detect([]string{"testdata/module.zip"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Project).To(Equal("testdata/module.zip"))
+			Expect(results[0].ErrStr).To(BeEmpty())
+			Expect(results[0].Matches).To(ContainElement(aMatchFor("BSD-3-Clause")))
+		})
+
+		It("should detect the licence of a zip without module prefix", func() {
+			// when
+			results, err := NewLicenceDetector().Detect([]string{"testdata/plain.zip"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Matches).To(ContainElement(aMatchFor("MIT")))
+		})
+
+		It("should detect the licence of a jar from its META-INF directory", func() {
+			// when
+			results, err := NewLicenceDetector().Detect([]string{"testdata/lib.jar"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].ErrStr).To(BeEmpty())
+			Expect(results[0].Matches).To(ContainElement(aMatchFor("BSD-3-Clause")))
+		})
+
+		It("should report an error when the zip has no licence", func() {
+			// when
+			results, err := NewLicenceDetector().Detect([]string{"testdata/no-licence.zip", "testdata/does-not-exist.zip"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+			Expect(results[0].ErrStr).ToNot(BeEmpty())
+			Expect(results[1].ErrStr).ToNot(BeEmpty())
+		})
+	})
+
+	Context("nested licences", func() {
+		It("should recognise licence file names", func() {
+			Expect(IsLicenceFile("LICENSE")).To(BeTrue())
+			Expect(IsLicenceFile("licence.md")).To(BeTrue())
+			Expect(IsLicenceFile("LICENSE-APACHE.txt")).To(BeTrue())
+			Expect(IsLicenceFile("COPYING")).To(BeTrue())
+			Expect(IsLicenceFile("license.go")).To(BeFalse())
+			Expect(IsLicenceFile("README.md")).To(BeFalse())
+		})
+
+		It("should detect the licence of projects from the directory of their declared licence file", func() {
+			Expect(Result{Project: "nested@1.0.0", Directory: "testdata/nested", LicenceFile: "docs/licenses/LICENSE-BSD.txt"}.LicenceDir()).
+				To(Equal(filepath.Join("testdata", "nested", "docs", "licenses")))
+			Expect(Result{Project: "nested@1.0.0", Directory: "testdata/nested", LicenceFile: "LICENSE"}.LicenceDir()).To(Equal("testdata/nested"))
+			Expect(Result{Project: "nested@1.0.0", Directory: "testdata/nested"}.LicenceDir()).To(Equal("testdata/nested"))
+			Expect(Result{Project: "testdata/nested"}.LicenceDir()).To(Equal("testdata/nested"))
+		})
+
+		It("should find the subdirectories with their own licence file", func() {
+			// when
+			dirs, err := FindNestedLicenceDirs("testdata/nested")
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dirs).To(Equal([]string{
+				filepath.Join("testdata", "nested", "docs", "licenses"),
+				filepath.Join("testdata", "nested", "third_party", "lib"),
+			}))
+		})
+
+		It("should name sub-components after their parent project", func() {
+			// given
+			project := Result{Project: "example.com/nested@v1.0.0", Module: "example.com/nested", Version: "v1.0.0", Directory: "testdata/nested",
+				DeclaredLicence: "MIT", Dependency: DependencyDirect}
+
+			// when
+			nested, err := NestedProjects(project)
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nested).To(HaveLen(2))
+			Expect(nested[1]).To(Equal(Result{
+				Project:   "example.com/nested@v1.0.0/third_party/lib",
+				Version:   "v1.0.0",
+				Directory: filepath.Join("testdata", "nested", "third_party", "lib"),
+				Parent:    "example.com/nested@v1.0.0",
+			}))
+		})
+
+		It("should not make a sub-component of the directory of the licence file declared by the project", func() {
+			// given
+			project := Result{Project: "nested@1.0.0", Directory: "testdata/nested", LicenceFile: "docs/licenses/LICENSE-BSD.txt"}
+
+			// when
+			nested, err := NestedProjects(project)
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nested).To(HaveLen(1))
+			Expect(nested[0].Project).To(Equal("nested@1.0.0/third_party/lib"))
+			Expect(nested[0].LicenceFile).To(BeEmpty())
+		})
+
+		It("should not find nested licences in zip archives", func() {
+			// when
+			dirs, err := FindNestedLicenceDirs("testdata/module.zip")
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dirs).To(BeEmpty())
+		})
+	})
+
+	Context("licence templates", func() {
+		It("should identify the licence of projects matching a licence template", func() {
+			// given
+			templates, err := LoadLicenceTemplates("testdata/templates")
+			Expect(err).ToNot(HaveOccurred())
+
+			// when
+			results, err := NewLicenceDetectorWithTemplates(templates).Detect([]string{"testdata/proprietary", "testdata/module.zip"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+			proprietary := results[1]
+			if proprietary.Project != "testdata/proprietary" {
+				proprietary = results[0]
+			}
+			Expect(proprietary.ErrStr).To(BeEmpty())
+			Expect(proprietary.Matches).To(HaveLen(1))
+			Expect(proprietary.Matches[0].Licence).To(Equal("LicenseRef-Example-Internal"))
+			Expect(proprietary.Matches[0].Confidence).To(BeNumerically(">", 0.95))
+			Expect(proprietary.Matches[0].Detector).To(Equal("files"))
+		})
+
+		It("should not match licence texts which differ from the templates", func() {
+			// given
+			templates, err := LoadLicenceTemplates("testdata/templates")
+			Expect(err).ToNot(HaveOccurred())
+
+			// when
+			results, err := NewLicenceDetectorWithTemplates(templates).Detect([]string{"testdata/module.zip"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].Matches).To(ContainElement(aMatchFor("BSD-3-Clause")))
+			Expect(results[0].Matches).ToNot(ContainElement(aMatchFor("LicenseRef-Example-Internal")))
+		})
+
+		It("should only accept templates named after a LicenseRef- identifier", func() {
+			// when
+			_, err := LoadLicenceTemplates("testdata/invalid-templates")
+
+			// then
+			Expect(err).To(MatchError(`licence template "Internal" should be named with a LicenseRef- identifier`))
+		})
+	})
+
+	Context("SPDX headers", func() {
+		It("should identify the licence of most files, counting the files of each header", func() {
+			// when
+			results, err := NewSPDXHeaderDetector().Detect([]string{"testdata/spdx-headers"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(Equal([]Result{{
+				Project: "testdata/spdx-headers",
+				Matches: []LicenceMatch{
+					{Licence: "Apache-2.0", Confidence: 1, Detector: "spdx-headers", Evidence: []Evidence{
+						evidenceOf("testdata/spdx-headers", "main.go", 1, 1),
+						evidenceOf("testdata/spdx-headers", "pkg/util.go", 2, 2),
+					}},
+					{Licence: "GPL-2.0-only", Confidence: 0.25, Detector: "spdx-headers", Evidence: []Evidence{
+						evidenceOf("testdata/spdx-headers", "third_party/parser.c", 1, 1),
+					}},
+					{Licence: "MIT OR Apache-2.0", Confidence: 0.25, Detector: "spdx-headers", Evidence: []Evidence{
+						evidenceOf("testdata/spdx-headers", "web/index.html", 1, 1),
+					}},
+				},
+				SPDXHeaders: []HeaderCount{
+					{Expression: "Apache-2.0", Files: 2},
+					{Expression: "GPL-2.0-only", Files: 1},
+					{Expression: "MIT OR Apache-2.0", Files: 1},
+				},
+				DifferingFiles: []FileLicence{
+					{Path: "third_party/parser.c", Licence: "GPL-2.0-only", Evidence: "spdx-header"},
+					{Path: "web/index.html", Licence: "MIT OR Apache-2.0", Evidence: "spdx-header"},
+				},
+			}}))
+		})
+
+		It("should report an error when no file has a header", func() {
+			// when
+			results, err := NewSPDXHeaderDetector().Detect([]string{"testdata/module.zip", "testdata/does-not-exist"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+			Expect(results[0].ErrStr).To(Equal("no SPDX-License-Identifier header was found"))
+			Expect(results[1].ErrStr).ToNot(BeEmpty())
+		})
+
+		It("should combine the headers of a file", func() {
+			Expect(spdxHeaderExpression([]byte("# SPDX-License-Identifier: MIT\n"))).To(Equal("MIT"))
+			Expect(spdxHeaderExpression([]byte("/*\n * SPDX-License-Identifier: GPL-2.0-or-later OR MIT\n * SPDX-License-Identifier: BSD-3-Clause\n */"))).To(Equal("(GPL-2.0-or-later OR MIT) AND BSD-3-Clause"))
+			Expect(spdxHeaderExpression([]byte("\x00SPDX-License-Identifier: MIT"))).To(BeEmpty())
+			Expect(spdxHeaderExpression([]byte("package main"))).To(BeEmpty())
+		})
+	})
+
+	Context("evidence", func() {
+		It("should report the licence files each licence is found in", func() {
+			// when
+			results, err := NewLicenceDetector().Detect([]string{"testdata/inventory", "testdata/lib.jar"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+			Expect(results[0].Project).To(Equal("testdata/lib.jar"))
+			Expect(results[0].Matches[0].Evidence).To(HaveLen(1))
+			Expect(results[0].Matches[0].Evidence[0].Path).To(Equal("META-INF/LICENSE.txt"))
+			Expect(results[0].Matches[0].Evidence[0].SHA256).To(HaveLen(64))
+			Expect(results[1].Project).To(Equal("testdata/inventory"))
+			for _, match := range results[1].Matches {
+				Expect(match.Evidence).To(Equal([]Evidence{evidenceOf("testdata/inventory", "LICENSE", 0, 0)}))
+			}
+		})
+
+		It("should report the licence file matching a licence template", func() {
+			// given
+			templates, err := LoadLicenceTemplates("testdata/templates")
+			Expect(err).ToNot(HaveOccurred())
+
+			// when
+			results, err := NewLicenceDetectorWithTemplates(templates).Detect([]string{"testdata/proprietary"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].Matches).To(HaveLen(1))
+			Expect(results[0].Matches[0].Evidence).To(Equal([]Evidence{evidenceOf("testdata/proprietary", "LICENSE", 0, 0)}))
+		})
+
+		It("should report the lines of the region a licence is found in", func() {
+			content := []byte("package main\n\n// SPDX-License-Identifier: MIT\n// SPDX-License-Identifier: BSD-3-Clause\n")
+
+			evidence := spdxHeaderEvidence("main.go", content)
+
+			Expect(evidence.Path).To(Equal("main.go"))
+			Expect(evidence.StartLine).To(Equal(3))
+			Expect(evidence.EndLine).To(Equal(4))
+		})
+	})
+
+	Context("copyrights", func() {
+		It("should extract the copyright holders and years of licence files, NOTICE files and file headers", func() {
+			// when
+			results, err := NewCopyrightDetector(NewLicenceDetector()).Detect([]string{"testdata/copyrights"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Matches).To(ContainElement(aMatchFor("MIT")))
+			Expect(results[0].Copyrights).To(Equal([]Copyright{
+				{Holder: "Example Corp", Years: "2015-2020"},
+				{Holder: "Jane Doe", Years: "2021-2022"},
+				{Holder: "The Apache Software Foundation", Years: "2014-2016"},
+				{Holder: "The Lib Authors"},
+			}))
+		})
+
+		It("should normalise the holder and years of copyright statements", func() {
+			Expect(parsedCopyright("# Copyright (C) 2009-2011, 2014 John Doe <john@example.com>")).To(Equal(copyrightStatement{holder: "John Doe", years: []int{2009, 2010, 2011, 2014}}))
+			Expect(parsedCopyright(" * Copyright 2018 The Go Authors. All rights reserved. */")).To(Equal(copyrightStatement{holder: "The Go Authors", years: []int{2018}}))
+			Expect(parsedCopyright("Copyright 2017-19 Example Ltd")).To(Equal(copyrightStatement{holder: "Example Ltd", years: []int{2017, 2018, 2019}}))
+			Expect(parsedCopyright("(c) Example Ltd")).To(Equal(copyrightStatement{holder: "Example Ltd"}))
+			Expect(parsedCopyright("Copyright [yyyy] [name of copyright owner]")).To(Equal(copyrightStatement{}))
+			Expect(parsedCopyright("copyright owner or by an individual or Legal Entity authorized to submit")).To(Equal(copyrightStatement{}))
+			Expect(parsedCopyright("package main")).To(Equal(copyrightStatement{}))
+		})
+
+		It("should end the ranges of years up to the present at the current year", func() {
+			// given
+			var years []int
+			for year := 2019; year <= time.Now().Year(); year++ {
+				years = append(years, year)
+			}
+
+			// then
+			Expect(parsedCopyright("Copyright: 2019-present by Example Ltd")).To(Equal(copyrightStatement{holder: "Example Ltd", years: years}))
+			Expect(parsedCopyright("Copyright (c) 2019 - Present Example Ltd")).To(Equal(copyrightStatement{holder: "Example Ltd", years: years}))
+		})
+
+		It("should format years as ranges of consecutive years", func() {
+			Expect(formatYears(map[int]bool{2015: true, 2016: true, 2017: true, 2020: true, 2022: true, 2023: true})).To(Equal("2015-2017, 2020, 2022-2023"))
+			Expect(formatYears(map[int]bool{})).To(BeEmpty())
+		})
+	})
+
+	Context("file inventory", func() {
+		It("should identify the licence of each file and report the files differing from the project licence", func() {
+			// when
+			results, err := NewInventoryDetector(NewLicenceDetector()).Detect([]string{"testdata/inventory"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Matches).To(ContainElement(aMatchFor("MIT")))
+			Expect(results[0].Inventory).To(Equal([]FileLicence{
+				{Path: "src/main.go", Licence: "MIT", Evidence: "spdx-header"},
+				{Path: "src/parser.c", Licence: "GPL-2.0-or-later", Evidence: "notice"},
+				{Path: "third_party/lib/LICENSE", Licence: "BSD-3-Clause", Evidence: "licence-file"},
+				{Path: "web/app.js", Licence: "MPL-2.0", Evidence: "notice"},
+			}))
+			Expect(results[0].DifferingFiles).To(Equal([]FileLicence{
+				{Path: "src/parser.c", Licence: "GPL-2.0-or-later", Evidence: "notice"},
+				{Path: "third_party/lib/LICENSE", Licence: "BSD-3-Clause", Evidence: "licence-file"},
+				{Path: "web/app.js", Licence: "MPL-2.0", Evidence: "notice"},
+			}))
+		})
+
+		It("should identify the licence notices in the header of files", func() {
+			Expect(classified("a.c", "# This library is free software; you can redistribute it under the terms of the GNU Lesser\n# General Public License as published by the Free Software Foundation; either version 2.1 of the License.")).To(Equal(FileLicence{Path: "a.c", Licence: "LGPL-2.1-only", Evidence: "notice"}))
+			Expect(classified("a.py", "# under the terms of the GNU Affero General Public License as published by\n# the Free Software Foundation, either version 3 of the License, or\n# (at your option) any later version.")).To(Equal(FileLicence{Path: "a.py", Licence: "AGPL-3.0-or-later", Evidence: "notice"}))
+			Expect(classified("a.java", "/*\n * Licensed under the Apache License, Version 2.0 (the \"License\");\n */")).To(Equal(FileLicence{Path: "a.java", Licence: "Apache-2.0", Evidence: "notice"}))
+			Expect(classified("LICENSE", "MIT")).To(Equal(FileLicence{}))
+			Expect(classified("a.go", "package main")).To(Equal(FileLicence{}))
+		})
+	})
+
+	Context("SPDX licences", func() {
+		It("should know the licences of the go-license-detector database, ignoring case and a trailing +", func() {
+			Expect(IsSPDXLicence("Apache-2.0")).To(BeTrue())
+			Expect(IsSPDXLicence("gpl-2.0+")).To(BeTrue())
+			Expect(IsSPDXLicence("LicenseRef-Proprietary")).To(BeTrue())
+			Expect(IsSPDXLicence("GPLv3")).To(BeFalse())
+		})
+
+		It("should know the newer licences which the checker produces", func() {
+			Expect(IsSPDXLicence("PSF-2.0")).To(BeTrue())
+			Expect(IsSPDXLicence("MIT-0")).To(BeTrue())
+			Expect(IsSPDXLicence("BlueOak-1.0.0")).To(BeTrue())
+			Expect(IsSPDXLicence("BSD-2-Clause-Views")).To(BeTrue())
+			Expect(IsSPDXLicence("Unicode-3.0")).To(BeTrue())
+		})
+
+		It("should know the added licences", func() {
+			Expect(IsSPDXLicence("Example-Added-1.0")).To(BeFalse())
+
+			// when
+			AddSPDXLicences("Example-Added-1.0")
+
+			// then
+			Expect(IsSPDXLicence("example-added-1.0")).To(BeTrue())
+		})
+	})
+
+	Context("detector chains", func() {
+		var files, declared *fakeDetector
+
+		BeforeEach(func() {
+			files = &fakeDetector{results: map[string]Result{
+				"a": {Project: "a", Matches: []LicenceMatch{{Licence: "MIT", Confidence: 0.9}}},
+				"b": {Project: "b", ErrStr: "no license file was found"},
+				"c": {Project: "c", ErrStr: "no license file was found"},
+			}}
+			declared = &fakeDetector{results: map[string]Result{
+				"a": {Project: "a", Matches: []LicenceMatch{{Licence: "Apache-2.0", Confidence: 0}}},
+				"b": {Project: "b", Matches: []LicenceMatch{{Licence: "LicenseRef-Proprietary", Confidence: 1}}},
+				"c": {Project: "c", ErrStr: "no declared licence"},
+			}}
+		})
+
+		It("should only run the next detectors for the paths whose licence is not detected yet with the fallback strategy", func() {
+			// when
+			results, err := NewChainDetector(StrategyFallback, NamedDetector{"files", files}, NamedDetector{"declared", declared}).Detect([]string{"a", "b", "c"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(declared.paths).To(Equal([]string{"b", "c"}))
+			Expect(results).To(Equal([]Result{
+				{Project: "a", Matches: []LicenceMatch{{Licence: "MIT", Confidence: 0.9, Detector: "files"}}},
+				{Project: "b", Matches: []LicenceMatch{{Licence: "LicenseRef-Proprietary", Confidence: 1, Detector: "declared"}}},
+				{Project: "c", ErrStr: "files: no license file was found; declared: no declared licence"},
+			}))
+		})
+
+		It("should use the first detector identifying a licence and flag disagreements with the first-hit strategy", func() {
+			// when
+			results, err := NewChainDetector(StrategyFirstHit, NamedDetector{"files", files}, NamedDetector{"declared", declared}).Detect([]string{"a", "b"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(declared.paths).To(Equal([]string{"a", "b"}))
+			Expect(results).To(Equal([]Result{
+				{
+					Project: "a",
+					Matches: []LicenceMatch{{Licence: "MIT", Confidence: 0.9, Detector: "files"}},
+					Disagreement: []LicenceMatch{
+						{Licence: "MIT", Confidence: 0.9, Detector: "files"},
+						{Licence: "Apache-2.0", Confidence: 0, Detector: "declared"},
+					},
+				},
+				{Project: "b", Matches: []LicenceMatch{{Licence: "LicenseRef-Proprietary", Confidence: 1, Detector: "declared"}}},
+			}))
+		})
+
+		It("should not identify licences the detectors disagree on with the consensus strategy", func() {
+			// when
+			results, err := NewChainDetector(StrategyConsensus, NamedDetector{"files", files}, NamedDetector{"declared", declared}).Detect([]string{"a", "b"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+			Expect(results[0].ErrStr).To(Equal("detectors disagree: files: MIT, declared: Apache-2.0"))
+			Expect(results[0].Matches).To(BeEmpty())
+			Expect(results[0].Disagreement).To(HaveLen(2))
+			Expect(results[1].Matches).To(Equal([]LicenceMatch{{Licence: "LicenseRef-Proprietary", Confidence: 1, Detector: "declared"}}))
+		})
+
+		It("should not flag the normalised licences the detectors agree on as disagreements", func() {
+			// given
+			files := &fakeDetector{results: map[string]Result{
+				"a": {Project: "a", Matches: []LicenceMatch{{Licence: "MIT", Confidence: 0.9}}},
+				"b": {Project: "b", Matches: []LicenceMatch{{Licence: "GPL-2.0", Confidence: 0.9}}},
+			}}
+			declared := &fakeDetector{results: map[string]Result{
+				"a": {Project: "a", Matches: []LicenceMatch{{Licence: "mit OR Apache-2.0", Confidence: 0}}},
+				"b": {Project: "b", Matches: []LicenceMatch{{Licence: "GPL-2.0-only", Confidence: 0}}},
+			}}
+
+			// when
+			results, err := NewChainDetector(StrategyConsensus, NamedDetector{"files", files}, NamedDetector{"declared", declared}).Detect([]string{"a", "b"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(Equal([]Result{
+				{Project: "a", Matches: []LicenceMatch{{Licence: "MIT", Confidence: 0.9, Detector: "files"}}},
+				{Project: "b", Matches: []LicenceMatch{{Licence: "GPL-2.0", Confidence: 0.9, Detector: "files"}}},
+			}))
+		})
+
+		It("should return the declared licences of the paths", func() {
+			// when
+			results, err := NewDeclaredDetector(map[string]string{"a": "MIT"}).Detect([]string{"a", "b"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(Equal([]Result{
+				{Project: "a", Matches: []LicenceMatch{{Licence: "MIT", Confidence: 0, Detector: "declared"}}},
+				{Project: "b", ErrStr: "no declared licence"},
+			}))
+		})
+
+		It("should only accept the known strategies", func() {
+			Expect(ParseStrategy("consensus")).To(Equal(StrategyConsensus))
+			_, err := ParseStrategy("majority")
+			Expect(err).To(MatchError(ContainSubstring(`invalid detection strategy "majority"`)))
+		})
+	})
+})
+
+type fakeDetector struct {
+	results map[string]Result
+	paths   []string
+}
+
+func (d *fakeDetector) Detect(paths []string) ([]Result, error) {
+	d.paths = paths
+	var results []Result
+	for _, path := range paths {
+		results = append(results, d.results[path])
+	}
+	return results, nil
+}
+
+func classified(filePath string, content string) FileLicence {
+	file, _ := classifyFile(filePath, []byte(content))
+	return file
+}
+
+// evidenceOf returns the evidence of a file of a testdata project, with the range of lines the licence is found on if any
+func evidenceOf(projectPath string, filePath string, startLine, endLine int) Evidence {
+	content, err := ioutil.ReadFile(filepath.Join(projectPath, filePath))
+	Expect(err).ToNot(HaveOccurred())
+	sum := sha256.Sum256(content)
+	return Evidence{Path: filePath, SHA256: hex.EncodeToString(sum[:]), StartLine: startLine, EndLine: endLine}
+}
+
+func parsedCopyright(line string) copyrightStatement {
+	statement, _ := parseCopyright(line)
+	return statement
+}
+
+func aMatchFor(licence string) types.GomegaMatcher {
+	return WithTransform(func(match LicenceMatch) string { return match.Licence }, Equal(licence))
+}
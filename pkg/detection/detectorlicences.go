@@ -0,0 +1,376 @@
+// Code generated by spdxlicences_gen.go; DO NOT EDIT.
+
+package detection
+
+// detectorLicences are the lower case identifiers of the licences of the go-license-detector database
+var detectorLicences = map[string]bool{
+	"0bsd":                                 true,
+	"aal":                                  true,
+	"abstyles":                             true,
+	"adobe-2006":                           true,
+	"adobe-glyph":                          true,
+	"adsl":                                 true,
+	"afl-1.1":                              true,
+	"afl-1.2":                              true,
+	"afl-2.0":                              true,
+	"afl-2.1":                              true,
+	"afl-3.0":                              true,
+	"afmparse":                             true,
+	"agpl-1.0":                             true,
+	"agpl-3.0":                             true,
+	"agpl-3.0-only":                        true,
+	"agpl-3.0-or-later":                    true,
+	"aladdin":                              true,
+	"amdplpa":                              true,
+	"aml":                                  true,
+	"ampas":                                true,
+	"antlr-pd":                             true,
+	"apache-1.0":                           true,
+	"apache-1.1":                           true,
+	"apache-2.0":                           true,
+	"apafml":                               true,
+	"apl-1.0":                              true,
+	"apsl-1.0":                             true,
+	"apsl-1.1":                             true,
+	"apsl-1.2":                             true,
+	"apsl-2.0":                             true,
+	"artistic-1.0":                         true,
+	"artistic-1.0-cl8":                     true,
+	"artistic-1.0-perl":                    true,
+	"artistic-2.0":                         true,
+	"bahyph":                               true,
+	"barr":                                 true,
+	"beerware":                             true,
+	"bittorrent-1.0":                       true,
+	"bittorrent-1.1":                       true,
+	"borceux":                              true,
+	"bsd-1-clause":                         true,
+	"bsd-2-clause":                         true,
+	"bsd-2-clause-freebsd":                 true,
+	"bsd-2-clause-netbsd":                  true,
+	"bsd-2-clause-patent":                  true,
+	"bsd-3-clause":                         true,
+	"bsd-3-clause-attribution":             true,
+	"bsd-3-clause-clear":                   true,
+	"bsd-3-clause-lbnl":                    true,
+	"bsd-3-clause-no-nuclear-license":      true,
+	"bsd-3-clause-no-nuclear-license-2014": true,
+	"bsd-3-clause-no-nuclear-warranty":     true,
+	"bsd-4-clause":                         true,
+	"bsd-4-clause-uc":                      true,
+	"bsd-protection":                       true,
+	"bsd-source-code":                      true,
+	"bsl-1.0":                              true,
+	"bzip2-1.0.5":                          true,
+	"bzip2-1.0.6":                          true,
+	"caldera":                              true,
+	"catosl-1.1":                           true,
+	"cc-by-1.0":                            true,
+	"cc-by-2.0":                            true,
+	"cc-by-2.5":                            true,
+	"cc-by-3.0":                            true,
+	"cc-by-4.0":                            true,
+	"cc-by-nc-1.0":                         true,
+	"cc-by-nc-2.0":                         true,
+	"cc-by-nc-2.5":                         true,
+	"cc-by-nc-3.0":                         true,
+	"cc-by-nc-4.0":                         true,
+	"cc-by-nc-nd-1.0":                      true,
+	"cc-by-nc-nd-2.0":                      true,
+	"cc-by-nc-nd-2.5":                      true,
+	"cc-by-nc-nd-3.0":                      true,
+	"cc-by-nc-nd-4.0":                      true,
+	"cc-by-nc-sa-1.0":                      true,
+	"cc-by-nc-sa-2.0":                      true,
+	"cc-by-nc-sa-2.5":                      true,
+	"cc-by-nc-sa-3.0":                      true,
+	"cc-by-nc-sa-4.0":                      true,
+	"cc-by-nd-1.0":                         true,
+	"cc-by-nd-2.0":                         true,
+	"cc-by-nd-2.5":                         true,
+	"cc-by-nd-3.0":                         true,
+	"cc-by-nd-4.0":                         true,
+	"cc-by-sa-1.0":                         true,
+	"cc-by-sa-2.0":                         true,
+	"cc-by-sa-2.5":                         true,
+	"cc-by-sa-3.0":                         true,
+	"cc-by-sa-4.0":                         true,
+	"cc0-1.0":                              true,
+	"cddl-1.0":                             true,
+	"cddl-1.1":                             true,
+	"cdla-permissive-1.0":                  true,
+	"cdla-sharing-1.0":                     true,
+	"cecill-1.0":                           true,
+	"cecill-1.1":                           true,
+	"cecill-2.0":                           true,
+	"cecill-2.1":                           true,
+	"cecill-b":                             true,
+	"cecill-c":                             true,
+	"clartistic":                           true,
+	"cnri-jython":                          true,
+	"cnri-python":                          true,
+	"cnri-python-gpl-compatible":           true,
+	"condor-1.1":                           true,
+	"cpal-1.0":                             true,
+	"cpl-1.0":                              true,
+	"cpol-1.02":                            true,
+	"crossword":                            true,
+	"crystalstacker":                       true,
+	"cua-opl-1.0":                          true,
+	"cube":                                 true,
+	"curl":                                 true,
+	"d-fsl-1.0":                            true,
+	"diffmark":                             true,
+	"doc":                                  true,
+	"dotseqn":                              true,
+	"dsdp":                                 true,
+	"dvipdfm":                              true,
+	"ecl-1.0":                              true,
+	"ecl-2.0":                              true,
+	"ecos-2.0":                             true,
+	"efl-1.0":                              true,
+	"efl-2.0":                              true,
+	"egenix":                               true,
+	"entessa":                              true,
+	"epl-1.0":                              true,
+	"epl-2.0":                              true,
+	"erlpl-1.1":                            true,
+	"eudatagrid":                           true,
+	"eupl-1.0":                             true,
+	"eupl-1.1":                             true,
+	"eupl-1.2":                             true,
+	"eurosym":                              true,
+	"fair":                                 true,
+	"frameworx-1.0":                        true,
+	"freeimage":                            true,
+	"fsfap":                                true,
+	"fsful":                                true,
+	"fsfullr":                              true,
+	"ftl":                                  true,
+	"gfdl-1.1":                             true,
+	"gfdl-1.1-only":                        true,
+	"gfdl-1.1-or-later":                    true,
+	"gfdl-1.2":                             true,
+	"gfdl-1.2-only":                        true,
+	"gfdl-1.2-or-later":                    true,
+	"gfdl-1.3":                             true,
+	"gfdl-1.3-only":                        true,
+	"gfdl-1.3-or-later":                    true,
+	"giftware":                             true,
+	"gl2ps":                                true,
+	"glide":                                true,
+	"glulxe":                               true,
+	"gnuplot":                              true,
+	"gpl-1.0":                              true,
+	"gpl-1.0+":                             true,
+	"gpl-1.0-only":                         true,
+	"gpl-1.0-or-later":                     true,
+	"gpl-2.0":                              true,
+	"gpl-2.0+":                             true,
+	"gpl-2.0-only":                         true,
+	"gpl-2.0-or-later":                     true,
+	"gpl-2.0-with-autoconf-exception":      true,
+	"gpl-2.0-with-bison-exception":         true,
+	"gpl-2.0-with-classpath-exception":     true,
+	"gpl-2.0-with-font-exception":          true,
+	"gpl-2.0-with-gcc-exception":           true,
+	"gpl-3.0":                              true,
+	"gpl-3.0+":                             true,
+	"gpl-3.0-only":                         true,
+	"gpl-3.0-or-later":                     true,
+	"gpl-3.0-with-autoconf-exception":      true,
+	"gpl-3.0-with-gcc-exception":           true,
+	"gsoap-1.3b":                           true,
+	"haskellreport":                        true,
+	"hpnd":                                 true,
+	"ibm-pibs":                             true,
+	"icu":                                  true,
+	"ijg":                                  true,
+	"imagemagick":                          true,
+	"imatix":                               true,
+	"imlib2":                               true,
+	"info-zip":                             true,
+	"intel":                                true,
+	"intel-acpi":                           true,
+	"interbase-1.0":                        true,
+	"ipa":                                  true,
+	"ipl-1.0":                              true,
+	"isc":                                  true,
+	"jasper-2.0":                           true,
+	"json":                                 true,
+	"lal-1.2":                              true,
+	"lal-1.3":                              true,
+	"latex2e":                              true,
+	"leptonica":                            true,
+	"lgpl-2.0":                             true,
+	"lgpl-2.0+":                            true,
+	"lgpl-2.0-only":                        true,
+	"lgpl-2.0-or-later":                    true,
+	"lgpl-2.1":                             true,
+	"lgpl-2.1+":                            true,
+	"lgpl-2.1-only":                        true,
+	"lgpl-2.1-or-later":                    true,
+	"lgpl-3.0":                             true,
+	"lgpl-3.0+":                            true,
+	"lgpl-3.0-only":                        true,
+	"lgpl-3.0-or-later":                    true,
+	"lgpllr":                               true,
+	"libpng":                               true,
+	"libtiff":                              true,
+	"liliq-p-1.1":                          true,
+	"liliq-r-1.1":                          true,
+	"liliq-rplus-1.1":                      true,
+	"lpl-1.0":                              true,
+	"lpl-1.02":                             true,
+	"lppl-1.0":                             true,
+	"lppl-1.1":                             true,
+	"lppl-1.2":                             true,
+	"lppl-1.3a":                            true,
+	"lppl-1.3c":                            true,
+	"makeindex":                            true,
+	"miros":                                true,
+	"mit":                                  true,
+	"mit-advertising":                      true,
+	"mit-cmu":                              true,
+	"mit-enna":                             true,
+	"mit-feh":                              true,
+	"mitnfa":                               true,
+	"motosoto":                             true,
+	"mpich2":                               true,
+	"mpl-1.0":                              true,
+	"mpl-1.1":                              true,
+	"mpl-2.0":                              true,
+	"mpl-2.0-no-copyleft-exception":        true,
+	"ms-pl":                                true,
+	"ms-rl":                                true,
+	"mtll":                                 true,
+	"multics":                              true,
+	"mup":                                  true,
+	"nasa-1.3":                             true,
+	"naumen":                               true,
+	"nbpl-1.0":                             true,
+	"ncsa":                                 true,
+	"net-snmp":                             true,
+	"netcdf":                               true,
+	"newsletr":                             true,
+	"ngpl":                                 true,
+	"nlod-1.0":                             true,
+	"nlpl":                                 true,
+	"nokia":                                true,
+	"nosl":                                 true,
+	"noweb":                                true,
+	"npl-1.0":                              true,
+	"npl-1.1":                              true,
+	"nposl-3.0":                            true,
+	"nrl":                                  true,
+	"ntp":                                  true,
+	"nunit":                                true,
+	"occt-pl":                              true,
+	"oclc-2.0":                             true,
+	"odbl-1.0":                             true,
+	"ofl-1.0":                              true,
+	"ofl-1.1":                              true,
+	"ogtsl":                                true,
+	"oldap-1.1":                            true,
+	"oldap-1.2":                            true,
+	"oldap-1.3":                            true,
+	"oldap-1.4":                            true,
+	"oldap-2.0":                            true,
+	"oldap-2.0.1":                          true,
+	"oldap-2.1":                            true,
+	"oldap-2.2":                            true,
+	"oldap-2.2.1":                          true,
+	"oldap-2.2.2":                          true,
+	"oldap-2.3":                            true,
+	"oldap-2.4":                            true,
+	"oldap-2.5":                            true,
+	"oldap-2.6":                            true,
+	"oldap-2.7":                            true,
+	"oldap-2.8":                            true,
+	"oml":                                  true,
+	"openssl":                              true,
+	"opl-1.0":                              true,
+	"oset-pl-2.1":                          true,
+	"osl-1.0":                              true,
+	"osl-1.1":                              true,
+	"osl-2.0":                              true,
+	"osl-2.1":                              true,
+	"osl-3.0":                              true,
+	"pddl-1.0":                             true,
+	"php-3.0":                              true,
+	"php-3.01":                             true,
+	"plexus":                               true,
+	"postgresql":                           true,
+	"psfrag":                               true,
+	"psutils":                              true,
+	"python-2.0":                           true,
+	"qhull":                                true,
+	"qpl-1.0":                              true,
+	"rdisc":                                true,
+	"rhecos-1.1":                           true,
+	"rpl-1.1":                              true,
+	"rpl-1.5":                              true,
+	"rpsl-1.0":                             true,
+	"rsa-md":                               true,
+	"rscpl":                                true,
+	"ruby":                                 true,
+	"sax-pd":                               true,
+	"saxpath":                              true,
+	"scea":                                 true,
+	"sendmail":                             true,
+	"sgi-b-1.0":                            true,
+	"sgi-b-1.1":                            true,
+	"sgi-b-2.0":                            true,
+	"simpl-2.0":                            true,
+	"sissl":                                true,
+	"sissl-1.2":                            true,
+	"sleepycat":                            true,
+	"smlnj":                                true,
+	"smppl":                                true,
+	"snia":                                 true,
+	"spencer-86":                           true,
+	"spencer-94":                           true,
+	"spencer-99":                           true,
+	"spl-1.0":                              true,
+	"standardml-nj":                        true,
+	"sugarcrm-1.1.3":                       true,
+	"swl":                                  true,
+	"tcl":                                  true,
+	"tcp-wrappers":                         true,
+	"tmate":                                true,
+	"torque-1.1":                           true,
+	"tosl":                                 true,
+	"unicode-dfs-2015":                     true,
+	"unicode-dfs-2016":                     true,
+	"unicode-tou":                          true,
+	"unlicense":                            true,
+	"upl-1.0":                              true,
+	"vim":                                  true,
+	"vostrom":                              true,
+	"vsl-1.0":                              true,
+	"w3c":                                  true,
+	"w3c-19980720":                         true,
+	"w3c-20150513":                         true,
+	"watcom-1.0":                           true,
+	"wsuipa":                               true,
+	"wtfpl":                                true,
+	"wxwindows":                            true,
+	"x11":                                  true,
+	"xerox":                                true,
+	"xfree86-1.1":                          true,
+	"xinetd":                               true,
+	"xnet":                                 true,
+	"xpp":                                  true,
+	"xskat":                                true,
+	"ypl-1.0":                              true,
+	"ypl-1.1":                              true,
+	"zed":                                  true,
+	"zend-2.0":                             true,
+	"zimbra-1.3":                           true,
+	"zimbra-1.4":                           true,
+	"zlib":                                 true,
+	"zlib-acknowledgement":                 true,
+	"zpl-1.1":                              true,
+	"zpl-2.0":                              true,
+	"zpl-2.1":                              true,
+}
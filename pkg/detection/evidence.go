@@ -0,0 +1,105 @@
+package detection
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Evidence is a file of a project a licence is detected from, so that the results can be audited
+type Evidence struct {
+	// Path is the slash separated path of the file, relative to the project
+	Path string `json:"path"`
+	// SHA256 is the hex encoded SHA-256 of the content of the file
+	SHA256 string `json:"sha256"`
+	// StartLine and EndLine are the lines of the file the licence is found on, when it is not the whole file
+	StartLine int `json:"startLine,omitempty"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// candidateLicenceFileRe matches the names of the files go-license-detector reads licence texts from
+var candidateLicenceFileRe = regexp.MustCompile(`^(|.*[-_. ])(li[cs]en[cs]e(s?)|legal|copy(left|right|ing)|unlicense|l?gpl([-_ v]?)(\d\.?\d)?|bsd|mit|apache)(|[-_. ].*)$`)
+
+// readmeFileRe matches the names of the README files go-license-detector reads licences from, for projects without
+// licence files
+var readmeFileRe = regexp.MustCompile(`^(readme|guidelines)(|\.md|\.rst|\.html|\.txt)$`)
+
+// fileEvidence returns the evidence of a licence found in the whole content of a file
+func fileEvidence(filePath string, content []byte) Evidence {
+	sum := sha256.Sum256(content)
+	return Evidence{Path: filePath, SHA256: hex.EncodeToString(sum[:])}
+}
+
+// regionEvidence returns the evidence of a licence found between the start and end byte offsets of the content of a file
+func regionEvidence(filePath string, content []byte, start, end int) Evidence {
+	evidence := fileEvidence(filePath, content)
+	evidence.StartLine = bytes.Count(content[:start], []byte("\n")) + 1
+	evidence.EndLine = evidence.StartLine + bytes.Count(content[start:end], []byte("\n"))
+	return evidence
+}
+
+// licenceFile is a licence file of a project along with the licences its text matches on its own
+type licenceFile struct {
+	evidence Evidence
+	licences map[string]bool
+}
+
+// addFileEvidence adds the licence files each match of the files detector is found in to its evidence. As
+// go-license-detector does not report the files it matches, the text of each licence file is detected on its own. The
+// matches of projects without licence files are found in their README files.
+func addFileEvidence(result *Result) {
+	f, dir, err := licenceFilesFiler(result.Project)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	files, err := f.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var licenceFiles []licenceFile
+	var readmes []Evidence
+	for _, file := range files {
+		if file.IsDir {
+			continue
+		}
+		name := strings.ToLower(file.Name)
+		isLicenceFile := candidateLicenceFileRe.MatchString(name)
+		if !isLicenceFile && !readmeFileRe.MatchString(name) {
+			continue
+		}
+		filePath := path.Join(dir, file.Name)
+		content, err := f.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		if !isLicenceFile {
+			readmes = append(readmes, fileEvidence(filePath, content))
+			continue
+		}
+		licences := make(map[string]bool)
+		for _, match := range DetectLicenceText(content) {
+			licences[match.Licence] = true
+		}
+		licenceFiles = append(licenceFiles, licenceFile{evidence: fileEvidence(filePath, content), licences: licences})
+	}
+
+	for i := range result.Matches {
+		match := &result.Matches[i]
+		if match.Detector != DetectorFiles || len(match.Evidence) > 0 {
+			continue
+		}
+		for _, file := range licenceFiles {
+			if file.licences[match.Licence] {
+				match.Evidence = append(match.Evidence, file.evidence)
+			}
+		}
+		if len(licenceFiles) == 0 {
+			match.Evidence = readmes
+		}
+	}
+}
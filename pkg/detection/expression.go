@@ -0,0 +1,193 @@
+package detection
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ExpressionRestricted returns true when the given licence cannot be used without complying with a restricted licence.
+// The licence may be an SPDX licence expression, e.g. `(MIT OR GPL-3.0)`, which is restricted when one of the licences
+// it combines with AND is restricted, or when all the licences it combines with OR are restricted. A licence with an
+// exception is restricted when either the licence with its exception or the licence alone is restricted.
+// Licences which are not valid expressions are compared as a whole.
+func ExpressionRestricted(licence string, restricted func(string) bool) bool {
+	if restricted(licence) {
+		return true
+	}
+	p := &expressionParser{tokens: tokenize(licence), restricted: restricted}
+	result, err := p.parseOr()
+	if err != nil || p.position != len(p.tokens) {
+		return false
+	}
+	return result
+}
+
+type expressionParser struct {
+	tokens     []string
+	position   int
+	restricted func(string) bool
+}
+
+func tokenize(expression string) []string {
+	expression = strings.Replace(expression, "(", " ( ", -1)
+	expression = strings.Replace(expression, ")", " ) ", -1)
+	return strings.Fields(expression)
+}
+
+func (p *expressionParser) next() string {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return ""
+}
+
+func (p *expressionParser) operator(name string) bool {
+	if strings.EqualFold(p.next(), name) {
+		p.position++
+		return true
+	}
+	return false
+}
+
+func (p *expressionParser) parseOr() (bool, error) {
+	result, err := p.parseAnd()
+	if err != nil {
+		return false, err
+	}
+	for p.operator("OR") {
+		alternative, err := p.parseAnd()
+		if err != nil {
+			return false, err
+		}
+		result = result && alternative
+	}
+	return result, nil
+}
+
+func (p *expressionParser) parseAnd() (bool, error) {
+	result, err := p.parseLicence()
+	if err != nil {
+		return false, err
+	}
+	for p.operator("AND") {
+		conjunct, err := p.parseLicence()
+		if err != nil {
+			return false, err
+		}
+		result = result || conjunct
+	}
+	return result, nil
+}
+
+func (p *expressionParser) parseLicence() (bool, error) {
+	token := p.next()
+	switch {
+	case token == "":
+		return false, fmt.Errorf("unexpected end of licence expression")
+	case token == "(":
+		p.position++
+		result, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if !p.operator(")") {
+			return false, fmt.Errorf("missing closing parenthesis in licence expression")
+		}
+		return result, nil
+	case token == ")" || strings.EqualFold(token, "AND") || strings.EqualFold(token, "OR") || strings.EqualFold(token, "WITH"):
+		return false, fmt.Errorf("unexpected %q in licence expression", token)
+	}
+
+	p.position++
+	licence := token
+	if p.operator("WITH") {
+		exception := p.next()
+		if exception == "" || exception == "(" || exception == ")" {
+			return false, fmt.Errorf("missing exception in licence expression")
+		}
+		p.position++
+		return p.restricted(licence+" WITH "+exception) || p.restricted(licence), nil
+	}
+	return p.restricted(licence), nil
+}
+
+// spdxLicenceRe matches the identifiers of SPDX licences, e.g. GPL-2.0-or-later or LicenseRef-In-House
+var spdxLicenceRe = regexp.MustCompile(`^[A-Za-z0-9.+-]+$`)
+
+// ExpressionLicences returns the licences combined by a valid SPDX licence expression, without their exceptions, or
+// false when the licence is not a valid expression, e.g. `The Apache Software License, Version 2.0`
+func ExpressionLicences(licence string) ([]string, bool) {
+	var licences []string
+	p := &expressionParser{tokens: tokenize(licence), restricted: func(licence string) bool {
+		if !strings.Contains(licence, " WITH ") {
+			licences = append(licences, licence)
+		}
+		return false
+	}}
+	if _, err := p.parseOr(); err != nil || p.position != len(p.tokens) {
+		return nil, false
+	}
+	for _, licence := range licences {
+		if !spdxLicenceRe.MatchString(licence) {
+			return nil, false
+		}
+	}
+	return licences, true
+}
+
+// IsSPDXExpression returns true when the licence is a valid SPDX licence expression whose licences are all SPDX
+// licences, e.g. `MIT OR Apache-2.0` but not `GPLv3`
+func IsSPDXExpression(licence string) bool {
+	licences, ok := ExpressionLicences(licence)
+	if !ok {
+		return false
+	}
+	for _, licence := range licences {
+		if !IsSPDXLicence(licence) {
+			return false
+		}
+	}
+	return true
+}
+
+// LicencesAgree returns true when two licences share one of their licences, once normalised with NormaliseLicence,
+// e.g. `MIT OR Apache-2.0` and `mit`, or `GPL-2.0` and `GPL-2.0-only`. Licences which are not valid expressions are
+// compared as a whole.
+func LicencesAgree(licence, other string) bool {
+	licences := make(map[string]bool)
+	for _, l := range licencesOf(licence) {
+		licences[NormaliseLicence(l)] = true
+	}
+	for _, l := range licencesOf(other) {
+		if licences[NormaliseLicence(l)] {
+			return true
+		}
+	}
+	return false
+}
+
+func licencesOf(licence string) []string {
+	if licences, ok := ExpressionLicences(licence); ok {
+		return licences
+	}
+	return []string{licence}
+}
+
+// NormaliseLicence returns the licence in lower case, with the deprecated SPDX identifiers of GNU licences replaced
+// with their current identifier, e.g. GPL-2.0+ with GPL-2.0-or-later and GPL-2.0 with GPL-2.0-only
+func NormaliseLicence(licence string) string {
+	licence = strings.ToLower(licence)
+	if strings.HasSuffix(licence, "+") {
+		return strings.TrimSuffix(licence, "+") + "-or-later"
+	}
+	if strings.HasSuffix(licence, "-only") || strings.HasSuffix(licence, "-or-later") {
+		return licence
+	}
+	for _, gnuLicence := range []string{"gpl-", "lgpl-", "agpl-", "gfdl-"} {
+		if strings.HasPrefix(licence, gnuLicence) {
+			return licence + "-only"
+		}
+	}
+	return licence
+}
@@ -0,0 +1,84 @@
+package detection
+
+import (
+	"bytes"
+	"gopkg.in/src-d/go-license-detector.v2/licensedb/filer"
+	"path"
+	"strings"
+)
+
+// projectFiler returns a filer for the files of a project, which is either a directory or a zip archive. For go module
+// zips, the files are read from their `<module path>@<version>/` directory.
+func projectFiler(projectPath string) (filer.Filer, error) {
+	if !isZip(projectPath) {
+		return filer.FromDirectory(projectPath)
+	}
+	zipFiler, prefix, err := newZipFiler(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	return filer.NestFiler(zipFiler, prefix), nil
+}
+
+// licenceFilesFiler returns the filer of a project along with the directory the files detector reads its licence files
+// from, which is the META-INF directory of jars without licence files at their root
+func licenceFilesFiler(projectPath string) (filer.Filer, string, error) {
+	f, err := projectFiler(projectPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if isJar(projectPath) && !hasLicenceFile(f, "") && hasLicenceFile(f, jarMetaInf) {
+		return f, jarMetaInf, nil
+	}
+	return f, "", nil
+}
+
+// hasLicenceFile returns true when the directory of the filer contains a licence file
+func hasLicenceFile(f filer.Filer, dir string) bool {
+	files, err := f.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, file := range files {
+		if !file.IsDir && IsLicenceFile(file.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// walkFiles calls fn with the slash separated path of each file of the filer, in lexical order. Hidden directories and
+// the directories of vendored dependencies, which are projects of their own, are skipped.
+func walkFiles(f filer.Filer, dir string, fn func(filePath string) error) error {
+	files, err := f.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		filePath := path.Join(dir, file.Name)
+		if file.IsDir {
+			if strings.HasPrefix(file.Name, ".") || file.Name == "vendor" || file.Name == "node_modules" {
+				continue
+			}
+			if err := walkFiles(f, filePath, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(filePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileHeader returns the beginning of the content of a file which is scanned for licence headers, or nil for binary files
+func fileHeader(content []byte) []byte {
+	if len(content) > headerSize {
+		content = content[:headerSize]
+	}
+	if bytes.IndexByte(content, 0) >= 0 {
+		return nil
+	}
+	return content
+}
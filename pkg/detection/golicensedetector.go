@@ -7,22 +7,56 @@ import (
 
 // goLicenseDetector is an implementation of LicenceDetector that uses `go-license-detector` to identify licences
 type goLicenseDetector struct {
+	templates []LicenceTemplate
 }
 
-// Detect actually invokes `go-Licence-detector` to perform the licence detection
+// Detect actually invokes `go-Licence-detector` to perform the licence detection.
+// Paths can either be project directories or zip archives, e.g. go modules zips from the module download cache.
 func (d *goLicenseDetector) Detect(paths []string) ([]Result, error) {
 	var results []Result
 
-	gldResults := golicensedetection.Analyse(paths...)
+	var dirs []string
+	for _, path := range paths {
+		if isZip(path) {
+			results = append(results, detectZip(path))
+		} else {
+			dirs = append(dirs, path)
+		}
+	}
+
+	gldResults := golicensedetection.Analyse(dirs...)
 	log.Debugf("Licence detection raw results from go-license-detector: %v", gldResults)
 	for _, gldResult := range gldResults {
 		results = append(results, buildResultFrom(gldResult))
 	}
 
+	for i := range results {
+		if len(d.templates) > 0 {
+			d.addTemplateMatches(&results[i])
+		}
+		if results[i].ErrStr == "" {
+			addFileEvidence(&results[i])
+		}
+	}
+
 	log.Tracef("Licence detection mapped results: %v", results)
 	return results, nil
 }
 
+// addTemplateMatches adds the matches of the licence templates to the result, which is identified by the templates
+// when go-license-detector does not identify it
+func (d *goLicenseDetector) addTemplateMatches(result *Result) {
+	matches := matchTemplates(result.Project, d.templates)
+	if len(matches) == 0 {
+		return
+	}
+	if result.ErrStr != "" {
+		result.ErrStr = ""
+		result.Matches = nil
+	}
+	result.Matches = append(result.Matches, matches...)
+}
+
 func buildResultFrom(gldResult golicensedetection.Result) Result {
 	result := Result{
 		Project: gldResult.Arg,
@@ -41,6 +75,7 @@ func buildLicenceMatchesFrom(gldMatches []golicensedetection.Match) []LicenceMat
 		licenceMatches = append(licenceMatches, LicenceMatch{
 			Licence:    gldMatch.License,
 			Confidence: gldMatch.Confidence,
+			Detector:   DetectorFiles,
 		})
 	}
 	return licenceMatches
@@ -0,0 +1,221 @@
+package detection
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	golicensedetection "gopkg.in/src-d/go-license-detector.v2/licensedb"
+	"gopkg.in/src-d/go-license-detector.v2/licensedb/filer"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// licenceNotice is the notice of a licence, as found in the header of the files it applies to
+type licenceNotice struct {
+	re *regexp.Regexp
+	// licence returns the licence of the notice from its submatches
+	licence func(submatches []string) string
+}
+
+// licenceNotices are the notices of the licences identified in the header of files, most specific first. They are
+// matched against the header of files in lower case, without comment markers.
+var licenceNotices = []licenceNotice{
+	{
+		re:      regexp.MustCompile(`gnu affero general public license (?:as published by the free software foundation,? )?(?:either )?version 3(?:(?: of the license)?,? or \(?at your option\)? any later version)?`),
+		licence: gnuLicence("AGPL-3.0"),
+	},
+	{
+		re: regexp.MustCompile(`gnu (?:lesser|library) general public license (?:as published by the free software foundation,? )?(?:either )?version (2\.1|2|3)(?:(?: of the license)?,? or \(?at your option\)? any later version)?`),
+		licence: func(submatches []string) string {
+			version := submatches[1]
+			if version == "2" {
+				version = "2.0"
+			}
+			return gnuLicence("LGPL-" + version)(submatches)
+		},
+	},
+	{
+		re: regexp.MustCompile(`gnu general public license (?:as published by the free software foundation,? )?(?:either )?version (2|3)(?:(?: of the license)?,? or \(?at your option\)? any later version)?`),
+		licence: func(submatches []string) string {
+			return gnuLicence("GPL-" + submatches[1] + ".0")(submatches)
+		},
+	},
+	{
+		re:      regexp.MustCompile(`mozilla public license,? v(?:ersion|\.)? ?2\.0`),
+		licence: func([]string) string { return "MPL-2.0" },
+	},
+	{
+		re:      regexp.MustCompile(`licensed under the apache license,? version 2\.0`),
+		licence: func([]string) string { return "Apache-2.0" },
+	},
+	{
+		re:      regexp.MustCompile(`permission is hereby granted,? free of charge,? to any person obtaining a copy`),
+		licence: func([]string) string { return "MIT" },
+	},
+}
+
+// gnuLicence returns the -only or -or-later licence of a GNU notice, depending on whether it allows any later version
+func gnuLicence(licence string) func(submatches []string) string {
+	return func(submatches []string) string {
+		if strings.HasSuffix(submatches[0], "any later version") {
+			return licence + "-or-later"
+		}
+		return licence + "-only"
+	}
+}
+
+// commentMarkersRe matches the comment markers and white spaces between the words of notices
+var commentMarkersRe = regexp.MustCompile(`(?:\s|//|/\*|\*/|[*#;]|--|<!--|-->)+`)
+
+// inventoryDetector is an implementation of LicenceDetector adding the licence of each file of projects to the results
+// of another detector
+type inventoryDetector struct {
+	detector LicenceDetector
+}
+
+// NewInventoryDetector creates a LicenceDetector which deep-scans the files of projects after running the given detector.
+// The licence of each file is identified from its SPDX-License-Identifier header, its licence text for licence files
+// nested in the project, or the licence notice in its header. These files are listed in the Inventory of the results,
+// and the files whose licence is not one of the matches of the project in their DifferingFiles.
+func NewInventoryDetector(detector LicenceDetector) LicenceDetector {
+	return &inventoryDetector{detector: detector}
+}
+
+// Detect returns the results of the detector, with the inventory of the files of each project
+func (d *inventoryDetector) Detect(paths []string) ([]Result, error) {
+	results, err := d.detector.Detect(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		result := &results[i]
+		inventory, err := fileInventory(result.Project)
+		if err != nil {
+			log.Warnf("Unable to scan the files of project '%s': %v", result.Project, err)
+			continue
+		}
+		result.Inventory = inventory
+		if result.ErrStr != "" {
+			continue
+		}
+
+		projectLicences := make(map[string]bool)
+		for _, match := range result.Matches {
+			projectLicences[match.Licence] = true
+		}
+		result.DifferingFiles = nil
+		for _, file := range inventory {
+			if !projectLicences[file.Licence] {
+				result.DifferingFiles = append(result.DifferingFiles, file)
+			}
+		}
+	}
+	return results, nil
+}
+
+// fileInventory returns the files of the project whose licence can be identified, ordered by path. The licence files
+// at the root of the project are the licence of the project itself, and are not included.
+func fileInventory(projectPath string) ([]FileLicence, error) {
+	f, err := projectFiler(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var inventory []FileLicence
+	err = walkFiles(f, "", func(filePath string) error {
+		content, err := f.ReadFile(filePath)
+		if err != nil {
+			// e.g. dangling symbolic links
+			return nil
+		}
+		if file, ok := classifyFile(filePath, content); ok {
+			inventory = append(inventory, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(inventory, func(i, j int) bool {
+		return inventory[i].Path < inventory[j].Path
+	})
+	return inventory, nil
+}
+
+// classifyFile identifies the licence of a file of a project from its content
+func classifyFile(filePath string, content []byte) (FileLicence, bool) {
+	if expression := spdxHeaderExpression(content); expression != "" {
+		return FileLicence{Path: filePath, Licence: expression, Evidence: EvidenceSPDXHeader}, true
+	}
+
+	if IsLicenceFile(path.Base(filePath)) {
+		if path.Dir(filePath) == "." {
+			return FileLicence{}, false
+		}
+		if licence := licenceFileLicence(content); licence != "" {
+			return FileLicence{Path: filePath, Licence: licence, Evidence: EvidenceLicenceFile}, true
+		}
+		return FileLicence{}, false
+	}
+
+	header := fileHeader(content)
+	if header == nil {
+		return FileLicence{}, false
+	}
+	text := commentMarkersRe.ReplaceAllString(strings.ToLower(string(header)), " ")
+	for _, notice := range licenceNotices {
+		if submatches := notice.re.FindStringSubmatch(text); submatches != nil {
+			return FileLicence{Path: filePath, Licence: notice.licence(submatches), Evidence: EvidenceNotice}, true
+		}
+	}
+	return FileLicence{}, false
+}
+
+// licenceFileLicence returns the most probable licence of the text of a licence file, as detected by go-license-detector
+func licenceFileLicence(content []byte) string {
+	matches := DetectLicenceText(content)
+	if len(matches) == 0 {
+		return ""
+	}
+	return mostProbableMatch(matches).Licence
+}
+
+// DetectLicenceText returns the licences matching a licence text on its own, as detected by go-license-detector
+func DetectLicenceText(text []byte) []LicenceMatch {
+	licences, err := golicensedetection.Detect(&licenceFileFiler{content: text})
+	if err != nil {
+		return nil
+	}
+	var matches []LicenceMatch
+	for licence, confidence := range licences {
+		matches = append(matches, LicenceMatch{Licence: licence, Confidence: confidence, Detector: DetectorFiles})
+	}
+	return matches
+}
+
+// licenceFileFiler is a filer holding a single licence file, to detect its licence on its own
+type licenceFileFiler struct {
+	content []byte
+}
+
+const licenceFileName = "LICENSE"
+
+func (l *licenceFileFiler) ReadFile(path string) ([]byte, error) {
+	if path != licenceFileName {
+		return nil, fmt.Errorf("no such file: %s", path)
+	}
+	return l.content, nil
+}
+
+func (l *licenceFileFiler) ReadDir(path string) ([]filer.File, error) {
+	if path != "" {
+		return nil, fmt.Errorf("no such directory: %s", path)
+	}
+	return []filer.File{{Name: licenceFileName}}, nil
+}
+
+func (l *licenceFileFiler) Close() {
+}
@@ -0,0 +1,88 @@
+package detection
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// nestedLicenceFileRe matches the names of licence files that mark a directory as a sub-component with its own licence terms
+var nestedLicenceFileRe = regexp.MustCompile(`^(licen[cs]es?|copying|unlicense)([-_][^.]*)?(\.md|\.txt|\.rst|\.html)?$`)
+
+// IsLicenceFile returns true when the file name is the name of a licence file, e.g. LICENSE, LICENCE.md or COPYING
+func IsLicenceFile(name string) bool {
+	return nestedLicenceFileRe.MatchString(strings.ToLower(name))
+}
+
+// FindNestedLicenceDirs returns the subdirectories of the project directory which contain their own licence files.
+// The project directory itself, hidden directories and the directories of vendored dependencies, which are projects of
+// their own, are not included. Projects which are not directories, e.g. zip archives, have no nested licence directories.
+func FindNestedLicenceDirs(projectDir string) ([]string, error) {
+	if info, err := os.Stat(projectDir); err != nil || !info.IsDir() {
+		return nil, err
+	}
+
+	var dirs []string
+	found := make(map[string]bool)
+	err := filepath.Walk(projectDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != projectDir && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor" || info.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		if dir == filepath.Clean(projectDir) || found[dir] || !IsLicenceFile(info.Name()) {
+			return nil
+		}
+		found[dir] = true
+		dirs = append(dirs, dir)
+		return nil
+	})
+	return dirs, err
+}
+
+// NestedProjects returns a sub-component project for each subdirectory of the project with its own licence files, other
+// than the directory of the licence file declared by the project.
+// Sub-components are named after the parent project and their relative directory, and keep the ecosystem and version of
+// their parent project. They have neither the module, declared licence nor dependency type of their parent project, so
+// that the module overrides and declared licence of the parent project do not apply to them.
+func NestedProjects(project Result) ([]Result, error) {
+	projectDir := project.Directory
+	if projectDir == "" {
+		projectDir = project.Project
+	}
+
+	dirs, err := FindNestedLicenceDirs(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var nested []Result
+	for _, dir := range dirs {
+		if dir == project.LicenceDir() {
+			// the declared licence file of the project itself
+			continue
+		}
+		relDir, err := filepath.Rel(projectDir, dir)
+		if err != nil {
+			return nil, err
+		}
+		subComponent := project
+		subComponent.Project = project.Project + "/" + filepath.ToSlash(relDir)
+		subComponent.Parent = project.Project
+		subComponent.Directory = dir
+		subComponent.Module = ""
+		subComponent.DeclaredLicence = ""
+		subComponent.Dependency = ""
+		subComponent.Replacement = nil
+		subComponent.LicenceFile = ""
+		nested = append(nested, subComponent)
+	}
+	return nested, nil
+}
@@ -0,0 +1,173 @@
+package detection
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DetectorSPDXHeaders detects licences from the SPDX-License-Identifier headers of the source files of projects
+const DetectorSPDXHeaders = "spdx-headers"
+
+// headerSize is the size of the beginning of files which is scanned for SPDX-License-Identifier headers and licence
+// notices
+const headerSize = 8192
+
+// spdxHeaderRe matches SPDX-License-Identifier headers, capturing their licence expression
+var spdxHeaderRe = regexp.MustCompile(`SPDX-License-Identifier:[ \t]*([^\r\n]*)`)
+
+// spdxCommentEndRe matches the end of comments which may follow the licence expression of headers, e.g. */ or -->
+var spdxCommentEndRe = regexp.MustCompile(`\s*(\*/|-->|\*\)|#}|%>)?\s*$`)
+
+// HeaderCount is the number of files of a project with the same SPDX-License-Identifier header
+type HeaderCount struct {
+	Expression string `json:"expression"`
+	Files      int    `json:"files"`
+}
+
+// FileLicence is the licence of a file of a project, relative to the project
+type FileLicence struct {
+	Path    string `json:"path"`
+	Licence string `json:"license"`
+	// Evidence is what the licence of the file is identified from, i.e. spdx-header, licence-file or notice
+	Evidence string `json:"evidence,omitempty"`
+	// Restricted is set by the compliance checks when the licence of the file is restricted
+	Restricted bool `json:"restricted,omitempty"`
+}
+
+// Evidence of the licence of files
+const (
+	EvidenceSPDXHeader  = "spdx-header"
+	EvidenceLicenceFile = "licence-file"
+	EvidenceNotice      = "notice"
+)
+
+// spdxHeaderDetector is an implementation of LicenceDetector using the SPDX-License-Identifier headers of source files
+type spdxHeaderDetector struct {
+}
+
+// NewSPDXHeaderDetector creates a LicenceDetector identifying the licence of projects from the SPDX-License-Identifier
+// headers of their files. The licence of a project is the expression of most of its files, with a confidence of 1, the
+// other expressions having the share of files they are found in as confidence. Files whose header differs from the
+// project licence are reported in the DifferingFiles of the result.
+func NewSPDXHeaderDetector() LicenceDetector {
+	return &spdxHeaderDetector{}
+}
+
+// Detect returns a result per path, in the order of the paths. Paths can either be project directories or zip archives.
+func (d *spdxHeaderDetector) Detect(paths []string) ([]Result, error) {
+	var results []Result
+	for _, path := range paths {
+		results = append(results, detectSPDXHeaders(path))
+	}
+	return results, nil
+}
+
+func detectSPDXHeaders(projectPath string) Result {
+	result := Result{Project: projectPath}
+
+	f, err := projectFiler(projectPath)
+	if err != nil {
+		result.ErrStr = err.Error()
+		return result
+	}
+	defer f.Close()
+
+	fileExpressions := make(map[string]string)
+	counts := make(map[string]int)
+	evidence := make(map[string][]Evidence)
+	err = walkFiles(f, "", func(filePath string) error {
+		content, err := f.ReadFile(filePath)
+		if err != nil {
+			// e.g. dangling symbolic links
+			return nil
+		}
+		if expression := spdxHeaderExpression(content); expression != "" {
+			fileExpressions[filePath] = expression
+			counts[expression]++
+			evidence[expression] = append(evidence[expression], spdxHeaderEvidence(filePath, content))
+		}
+		return nil
+	})
+	if err != nil {
+		result.ErrStr = err.Error()
+		return result
+	}
+	if len(fileExpressions) == 0 {
+		result.ErrStr = "no SPDX-License-Identifier header was found"
+		return result
+	}
+
+	for expression, count := range counts {
+		result.SPDXHeaders = append(result.SPDXHeaders, HeaderCount{Expression: expression, Files: count})
+	}
+	sort.Slice(result.SPDXHeaders, func(i, j int) bool {
+		if result.SPDXHeaders[i].Files == result.SPDXHeaders[j].Files {
+			return result.SPDXHeaders[i].Expression < result.SPDXHeaders[j].Expression
+		}
+		return result.SPDXHeaders[i].Files > result.SPDXHeaders[j].Files
+	})
+
+	projectLicence := result.SPDXHeaders[0].Expression
+	for i, header := range result.SPDXHeaders {
+		confidence := float32(header.Files) / float32(len(fileExpressions))
+		if i == 0 {
+			confidence = 1
+		}
+		result.Matches = append(result.Matches, LicenceMatch{Licence: header.Expression, Confidence: confidence, Detector: DetectorSPDXHeaders, Evidence: evidence[header.Expression]})
+	}
+	result.DifferingFiles = differingFiles(fileExpressions, projectLicence)
+	return result
+}
+
+// spdxHeaderExpression returns the licence expression of the SPDX-License-Identifier headers at the beginning of the
+// content, or an empty string for binary files and files without header. Several headers are combined with AND.
+func spdxHeaderExpression(content []byte) string {
+	content = fileHeader(content)
+	if content == nil {
+		return ""
+	}
+
+	var expressions []string
+	seen := make(map[string]bool)
+	for _, match := range spdxHeaderRe.FindAllSubmatch(content, -1) {
+		expression := spdxCommentEndRe.ReplaceAllString(string(match[1]), "")
+		expression = strings.TrimSpace(expression)
+		if expression == "" || seen[expression] {
+			continue
+		}
+		seen[expression] = true
+		expressions = append(expressions, expression)
+	}
+	if len(expressions) <= 1 {
+		return strings.Join(expressions, "")
+	}
+	for i, expression := range expressions {
+		if strings.ContainsRune(expression, ' ') && !strings.HasPrefix(expression, "(") {
+			expressions[i] = fmt.Sprintf("(%s)", expression)
+		}
+	}
+	return strings.Join(expressions, " AND ")
+}
+
+// spdxHeaderEvidence returns the evidence of the SPDX-License-Identifier headers of a file, from the first to the last
+// header line
+func spdxHeaderEvidence(filePath string, content []byte) Evidence {
+	headers := spdxHeaderRe.FindAllIndex(fileHeader(content), -1)
+	return regionEvidence(filePath, content, headers[0][0], headers[len(headers)-1][1])
+}
+
+// differingFiles returns the files whose licence differs from the licence of the project, ordered by path
+func differingFiles(fileLicences map[string]string, projectLicence string) []FileLicence {
+	var files []FileLicence
+	for filePath, licence := range fileLicences {
+		if licence != projectLicence {
+			files = append(files, FileLicence{Path: filePath, Licence: licence, Evidence: EvidenceSPDXHeader})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
@@ -0,0 +1,51 @@
+package detection
+
+import (
+	"strings"
+	"sync"
+)
+
+//go:generate go run spdxlicences_gen.go
+
+// newerLicences are the lower case identifiers of the licences of the SPDX licence list which are newer than the
+// go-license-detector database, and which the checker produces itself, e.g. from python trove classifiers
+var newerLicences = map[string]bool{
+	"blueoak-1.0.0":      true,
+	"bsd-2-clause-views": true,
+	"mit-0":              true,
+	"psf-2.0":            true,
+	"unicode-3.0":        true,
+}
+
+var (
+	// addedLicences are the lower case identifiers of the licences added with AddSPDXLicences
+	addedLicences   = map[string]bool{}
+	addedLicencesMu sync.RWMutex
+)
+
+// AddSPDXLicences adds the identifiers of licences which are unknown to the go-license-detector database to the SPDX
+// licences, e.g. the licences of an imported SPDX licence list
+func AddSPDXLicences(licences ...string) {
+	addedLicencesMu.Lock()
+	defer addedLicencesMu.Unlock()
+	for _, licence := range licences {
+		addedLicences[strings.ToLower(licence)] = true
+	}
+}
+
+// IsSPDXLicence returns true when the identifier is the identifier of a licence of the SPDX licence list, ignoring case,
+// including the deprecated identifiers and the identifiers followed by +, or a LicenseRef- or DocumentRef- identifier.
+// The SPDX licences are those of the go-license-detector database, the newer licences the checker produces, and the
+// licences added with AddSPDXLicences.
+func IsSPDXLicence(identifier string) bool {
+	if strings.HasPrefix(identifier, licenceRefPrefix) || strings.HasPrefix(identifier, "DocumentRef-") {
+		return true
+	}
+	licence := strings.ToLower(strings.TrimSuffix(identifier, "+"))
+	if detectorLicences[licence] || newerLicences[licence] {
+		return true
+	}
+	addedLicencesMu.RLock()
+	defer addedLicencesMu.RUnlock()
+	return addedLicences[licence]
+}
@@ -0,0 +1,96 @@
+//go:build ignore
+// +build ignore
+
+// spdxlicences_gen generates detectorlicences.go, the identifiers of the licences of the go-license-detector database,
+// from the names.csv asset embedded in its vendored bindata.go. Run with go generate from the detection package.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	bindataFile = "../../vendor/gopkg.in/src-d/go-license-detector.v2/licensedb/internal/assets/bindata.go"
+	namesVar    = "_namesCsv"
+	output      = "detectorlicences.go"
+)
+
+func main() {
+	names, err := namesCsv()
+	if err != nil {
+		log.Fatalf("unable to read the names.csv asset of go-license-detector: %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(names)).ReadAll()
+	if err != nil {
+		log.Fatalf("unable to parse the names.csv asset of go-license-detector: %v", err)
+	}
+
+	var licences []string
+	for _, record := range records {
+		licences = append(licences, strings.ToLower(record[0]))
+	}
+	sort.Strings(licences)
+
+	var source bytes.Buffer
+	source.WriteString("// Code generated by spdxlicences_gen.go; DO NOT EDIT.\n\npackage detection\n\n")
+	source.WriteString("// detectorLicences are the lower case identifiers of the licences of the go-license-detector database\n")
+	source.WriteString("var detectorLicences = map[string]bool{\n")
+	for _, licence := range licences {
+		fmt.Fprintf(&source, "\t%q: true,\n", licence)
+	}
+	source.WriteString("}\n")
+
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		log.Fatalf("unable to format %s: %v", output, err)
+	}
+	if err := ioutil.WriteFile(output, formatted, 0644); err != nil {
+		log.Fatalf("unable to write %s: %v", output, err)
+	}
+}
+
+// namesCsv returns the uncompressed names.csv asset, which bindata.go holds as a gzipped byte slice literal
+func namesCsv() ([]byte, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), bindataFile, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var literal *ast.BasicLit
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok || len(spec.Names) != 1 || spec.Names[0].Name != namesVar || len(spec.Values) != 1 {
+			return true
+		}
+		if call, ok := spec.Values[0].(*ast.CallExpr); ok && len(call.Args) == 1 {
+			literal, _ = call.Args[0].(*ast.BasicLit)
+		}
+		return false
+	})
+	if literal == nil {
+		return nil, fmt.Errorf("%s not found in %s", namesVar, bindataFile)
+	}
+
+	compressed, err := strconv.Unquote(literal.Value)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := gzip.NewReader(strings.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
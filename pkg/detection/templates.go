@@ -0,0 +1,169 @@
+package detection
+
+import (
+	"fmt"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// licenceRefPrefix is the prefix of the identifiers of the licences which are not in the SPDX licence list
+const licenceRefPrefix = "LicenseRef-"
+
+// templateSimilarityThreshold is the minimum similarity of a licence file with a licence template to match it, as used
+// by go-license-detector for the SPDX licences
+const templateSimilarityThreshold = 0.75
+
+var (
+	// templateCopyrightLineRe matches the copyright lines of licences, which differ between projects
+	templateCopyrightLineRe = regexp.MustCompile(`(?m)^\s*(copyright|\(c\)|©|all rights reserved).*$`)
+	// templateDashesRe and templateQuotesRe match the variations of dashes and quotes, which are equivalent
+	templateDashesRe = regexp.MustCompile(`[-‒–—―~‐‑−]+`)
+	templateQuotesRe = regexp.MustCompile("[\"'“”‘’„‚«»`]+")
+	// templateNonAlphaNumRe matches the characters which are not significant to match licences
+	templateNonAlphaNumRe = regexp.MustCompile(`[^- \na-z0-9]`)
+	templateWordReplacer  = strings.NewReplacer("licence", "license", "sub-license", "sublicense", "whilst", "while")
+)
+
+// LicenceTemplate is the text of a licence which is unknown to go-license-detector, e.g. a proprietary licence with a
+// LicenseRef- identifier or a licence of a newer SPDX licence list, along with its identifier
+type LicenceTemplate struct {
+	Licence string
+	tokens  []string
+}
+
+// NewLicenceTemplate creates the template of the licence with the given identifier and text
+func NewLicenceTemplate(licence string, text string) (LicenceTemplate, error) {
+	tokens := licenceTokens(text)
+	if len(tokens) == 0 {
+		return LicenceTemplate{}, fmt.Errorf("licence template %s has no text", licence)
+	}
+	return LicenceTemplate{Licence: licence, tokens: tokens}, nil
+}
+
+// LoadLicenceTemplates reads the licence templates of a directory. Each file holds the text of a licence, and is named
+// after its LicenseRef- identifier, with an optional extension, e.g. LicenseRef-Sky-Internal.txt.
+func LoadLicenceTemplates(dir string) ([]LicenceTemplate, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read licence templates: %v", err)
+	}
+
+	var templates []LicenceTemplate
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		text, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read licence template: %v", err)
+		}
+		licence := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		if !strings.HasPrefix(licence, licenceRefPrefix) || len(licence) == len(licenceRefPrefix) {
+			return nil, fmt.Errorf("licence template %q should be named with a %s identifier", licence, licenceRefPrefix)
+		}
+		template, err := NewLicenceTemplate(licence, string(text))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+// similarity returns the similarity of the words of a licence text with the template, from 0 to 1. As for the SPDX
+// licences of go-license-detector, this is 1 minus the Levenshtein distance between the words of the text and of the
+// template, relative to the number of words of the text, where consecutive words which are not in the template count as
+// one. Templates whose length alone makes them less similar than the minimum similarity are not compared, and are 0.
+func (t LicenceTemplate) similarity(textTokens []string, minimum float32) float32 {
+	vocabulary := make(map[string]rune)
+	templateRunes := make([]rune, 0, len(t.tokens))
+	for _, token := range t.tokens {
+		index, ok := vocabulary[token]
+		if !ok {
+			index = rune(len(vocabulary))
+			vocabulary[token] = index
+		}
+		templateRunes = append(templateRunes, index)
+	}
+
+	unknownRune := rune(len(vocabulary))
+	var textRunes []rune
+	for _, token := range textTokens {
+		if index, ok := vocabulary[token]; ok {
+			textRunes = append(textRunes, index)
+		} else if len(textRunes) == 0 || textRunes[len(textRunes)-1] != unknownRune {
+			textRunes = append(textRunes, unknownRune)
+		}
+	}
+	if len(textRunes) == 0 {
+		return 0
+	}
+	// the distance is at least the difference of lengths
+	lengthDifference := len(templateRunes) - len(textRunes)
+	if lengthDifference < 0 {
+		lengthDifference = -lengthDifference
+	}
+	if 1-float32(lengthDifference)/float32(len(textRunes)) < minimum {
+		return 0
+	}
+
+	dmp := diffmatchpatch.New()
+	distance := dmp.DiffLevenshtein(dmp.DiffMainRunes(textRunes, templateRunes, false))
+	return 1 - float32(distance)/float32(len(textRunes))
+}
+
+// licenceTokens returns the words of a licence text, normalised following the SPDX matching guidelines: case,
+// punctuation, varietal spellings and copyright lines are not significant
+func licenceTokens(text string) []string {
+	text = strings.ToLower(text)
+	text = templateCopyrightLineRe.ReplaceAllString(text, "")
+	text = templateDashesRe.ReplaceAllString(text, "-")
+	text = templateQuotesRe.ReplaceAllString(text, "")
+	text = templateWordReplacer.Replace(text)
+	text = templateNonAlphaNumRe.ReplaceAllString(text, " ")
+	return strings.Fields(text)
+}
+
+// matchTemplates returns the matches of the licence templates with the licence files at the root of the project, with
+// the file matching each template best as evidence
+func matchTemplates(projectPath string, templates []LicenceTemplate) []LicenceMatch {
+	f, err := projectFiler(projectPath)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	files, err := f.ReadDir("")
+	if err != nil {
+		return nil
+	}
+
+	confidences := make(map[string]float32)
+	evidence := make(map[string]Evidence)
+	for _, file := range files {
+		if file.IsDir || !IsLicenceFile(file.Name) {
+			continue
+		}
+		content, err := f.ReadFile(file.Name)
+		if err != nil {
+			continue
+		}
+		tokens := licenceTokens(string(content))
+		for _, template := range templates {
+			if similarity := template.similarity(tokens, templateSimilarityThreshold); similarity > confidences[template.Licence] {
+				confidences[template.Licence] = similarity
+				evidence[template.Licence] = fileEvidence(file.Name, content)
+			}
+		}
+	}
+
+	var matches []LicenceMatch
+	for _, template := range templates {
+		if confidence := confidences[template.Licence]; confidence >= templateSimilarityThreshold {
+			matches = append(matches, LicenceMatch{Licence: template.Licence, Confidence: confidence, Detector: DetectorFiles, Evidence: []Evidence{evidence[template.Licence]}})
+		}
+	}
+	return matches
+}
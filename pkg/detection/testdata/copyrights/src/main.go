@@ -0,0 +1,7 @@
+// Copyright © 2021 Jane Doe <jane@example.com>
+// SPDX-FileCopyrightText: 2022 Jane  Doe
+
+package main
+
+func main() {
+}
@@ -0,0 +1,7 @@
+/*
+ * Copyright (C) <year>  <name of author>
+ *
+ * Copyright owners of this file may be found in the NOTICE file.
+ */
+
+int parse(void);
@@ -0,0 +1,4 @@
+// Copyright 2024 Example Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pkg
@@ -0,0 +1,172 @@
+package detection
+
+import (
+	"archive/zip"
+	"fmt"
+	golicensedetection "gopkg.in/src-d/go-license-detector.v2/licensedb"
+	"gopkg.in/src-d/go-license-detector.v2/licensedb/filer"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// isZip returns true when the path is a zip archive rather than a project directory, e.g. a go module zip or a java jar
+func isZip(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".zip") || isJar(path)
+}
+
+func isJar(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".jar")
+}
+
+// detectZip runs the licence detection against the files of a zip archive.
+// For go module zips, whose files are all nested under `<module path>@<version>/`, the detection runs from that directory.
+// For jars without licence files at their root, the detection runs from their META-INF directory.
+func detectZip(path string) Result {
+	result := Result{Project: path}
+
+	zipFiler, prefix, err := newZipFiler(path)
+	if err != nil {
+		result.ErrStr = err.Error()
+		return result
+	}
+	defer zipFiler.Close()
+
+	if prefix == "" && isJar(path) && !hasLicenceFile(zipFiler, "") && hasLicenceFile(zipFiler, jarMetaInf) {
+		prefix = jarMetaInf
+	}
+
+	licences, err := golicensedetection.Detect(filer.NestFiler(zipFiler, prefix))
+	if err != nil {
+		result.ErrStr = err.Error()
+		return result
+	}
+
+	var matches []golicensedetection.Match
+	for licence, confidence := range licences {
+		matches = append(matches, golicensedetection.Match{License: licence, Confidence: confidence})
+	}
+	result.Matches = buildLicenceMatchesFrom(matches)
+	return result
+}
+
+// jarMetaInf is the directory of jars holding their metadata, including their licence files
+const jarMetaInf = "META-INF"
+
+// zipNode is a file or directory of a zip archive.
+// filer.FromZIP is not used as it expects explicit entries for every directory, which go module zips do not have.
+type zipNode struct {
+	children map[string]*zipNode
+	file     *zip.File
+}
+
+type zipFiler struct {
+	archive *zip.ReadCloser
+	root    *zipNode
+}
+
+// newZipFiler returns a filer for the files of the zip archive, along with the go module prefix of its files if any
+func newZipFiler(path string) (*zipFiler, string, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read zip archive %s: %v", path, err)
+	}
+
+	root := &zipNode{children: make(map[string]*zipNode)}
+	for _, f := range archive.File {
+		node := root
+		for _, part := range strings.Split(f.Name, "/") {
+			if part == "" {
+				continue
+			}
+			child, ok := node.children[part]
+			if !ok {
+				child = &zipNode{children: make(map[string]*zipNode)}
+				node.children[part] = child
+			}
+			node = child
+		}
+		if !strings.HasSuffix(f.Name, "/") {
+			node.file = f
+		}
+	}
+	return &zipFiler{archive: archive, root: root}, modulePrefix(archive.File), nil
+}
+
+// modulePrefix returns the `<module path>@<version>` directory all the files of a go module zip are nested into,
+// or an empty prefix when the files are not nested that way
+func modulePrefix(files []*zip.File) string {
+	var prefix string
+	for _, f := range files {
+		at := strings.Index(f.Name, "@")
+		if at < 0 {
+			return ""
+		}
+		slash := strings.Index(f.Name[at:], "/")
+		if slash < 0 {
+			return ""
+		}
+		filePrefix := f.Name[:at+slash]
+		if prefix != "" && filePrefix != prefix {
+			return ""
+		}
+		prefix = filePrefix
+	}
+	return filepath.FromSlash(prefix)
+}
+
+func (z *zipFiler) node(path string) (*zipNode, error) {
+	node := z.root
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if part == "" || part == "." {
+			continue
+		}
+		child, ok := node.children[part]
+		if !ok {
+			return nil, fmt.Errorf("does not exist: %s", path)
+		}
+		node = child
+	}
+	return node, nil
+}
+
+func (z *zipFiler) ReadFile(path string) ([]byte, error) {
+	node, err := z.node(path)
+	if err != nil {
+		return nil, err
+	}
+	if node.file == nil {
+		return nil, fmt.Errorf("not a file: %s", path)
+	}
+
+	reader, err := node.file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("cannot open %s: %v", path, err)
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+func (z *zipFiler) ReadDir(path string) ([]filer.File, error) {
+	node, err := z.node(path)
+	if err != nil {
+		return nil, err
+	}
+	if node.file != nil {
+		return nil, fmt.Errorf("not a directory: %s", path)
+	}
+
+	var files []filer.File
+	for name, child := range node.children {
+		files = append(files, filer.File{Name: name, IsDir: child.file == nil})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files, nil
+}
+
+func (z *zipFiler) Close() {
+	z.archive.Close()
+}
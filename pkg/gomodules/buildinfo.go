@@ -0,0 +1,31 @@
+//go:build go1.18
+// +build go1.18
+
+package gomodules
+
+import (
+	"debug/buildinfo"
+	"fmt"
+)
+
+// BinaryModules returns the go modules embedded in the build information of the given go executable, including its main module.
+// Executables built outside of a go module, e.g. in GOPATH mode, have no module information and are rejected.
+func BinaryModules(path string) ([]Module, error) {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read build information of %s: %v", path, err)
+	}
+	if info.Main.Path == "" {
+		return nil, fmt.Errorf("%s has no go module build information, it was not built from a go module", path)
+	}
+
+	modules := []Module{{Path: info.Main.Path, Version: info.Main.Version, Sum: info.Main.Sum, Main: true}}
+	for _, dep := range info.Deps {
+		module := Module{Path: dep.Path, Version: dep.Version, Sum: dep.Sum}
+		if dep.Replace != nil {
+			module.Replace = &Module{Path: dep.Replace.Path, Version: dep.Replace.Version, Sum: dep.Replace.Sum}
+		}
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
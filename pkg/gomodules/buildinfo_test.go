@@ -0,0 +1,64 @@
+//go:build go1.18
+// +build go1.18
+
+package gomodules
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sky-uk/licence-compliance-checker/pkg/gomodules/gomodulestest"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("go executables", func() {
+	var binDir string
+
+	BeforeEach(func() {
+		var err error
+		binDir, err = ioutil.TempDir("", "binaries")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(binDir)
+	})
+
+	It("should list the main module and the dependencies of the build information, with their replacements", func() {
+		// given
+		binary, err := gomodulestest.BuildBinary("testdata/binary-module", filepath.Join(binDir, "binary-module"), "GO111MODULE=on", "GOFLAGS=-mod=vendor")
+		Expect(err).ToNot(HaveOccurred())
+
+		// when
+		modules, err := BinaryModules(binary)
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(modules).To(Equal([]Module{
+			{Path: "example.com/binary-module", Version: "(devel)", Main: true},
+			{Path: "example.com/dep", Version: "v1.2.0"},
+			{Path: "example.com/old", Version: "v1.0.0", Replace: &Module{Path: "github.com/Some-Org/original", Version: "v1.0.0"}},
+		}))
+	})
+
+	It("should fail for executables built outside of a go module", func() {
+		// given
+		binary, err := gomodulestest.BuildBinary("testdata/gopath-binary", filepath.Join(binDir, "gopath-binary"), "GO111MODULE=off")
+		Expect(err).ToNot(HaveOccurred())
+
+		// when
+		_, err = BinaryModules(binary)
+
+		// then
+		Expect(err).To(MatchError(binary + " has no go module build information, it was not built from a go module"))
+	})
+
+	It("should fail for files which are not go executables", func() {
+		// when
+		_, err := BinaryModules("testdata/go.sum")
+
+		// then
+		Expect(err).To(HaveOccurred())
+	})
+})
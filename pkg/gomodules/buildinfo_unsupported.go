@@ -0,0 +1,12 @@
+//go:build !go1.18
+// +build !go1.18
+
+package gomodules
+
+import "errors"
+
+// BinaryModules returns the go modules embedded in the build information of the given go executable, including its main module.
+// Reading the build information requires the checker to be built with go 1.18 or later.
+func BinaryModules(path string) ([]Module, error) {
+	return nil, errors.New("reading go executables build information requires licence-compliance-checker to be built with go 1.18 or later")
+}
@@ -0,0 +1,58 @@
+package gomodules
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// GoMod is the name of the file declaring a go module and its requirements
+const GoMod = "go.mod"
+
+// DirectRequirements returns the paths of the modules required by the given go.mod file which are not marked `// indirect`,
+// i.e. the modules imported by the main module itself.
+// Any other module of the module graph is only an indirect dependency of the main module.
+func DirectRequirements(goModPath string) (map[string]bool, error) {
+	f, err := os.Open(goModPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	direct := make(map[string]bool)
+	inRequireBlock := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case inRequireBlock && line == ")":
+			inRequireBlock = false
+			continue
+		case inRequireBlock:
+		case strings.HasPrefix(line, "require") && strings.TrimSpace(strings.TrimPrefix(line, "require")) == "(":
+			inRequireBlock = true
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimPrefix(line, "require ")
+		default:
+			continue
+		}
+
+		requirement := line
+		var comment string
+		if i := strings.Index(line, "//"); i >= 0 {
+			requirement, comment = line[:i], strings.TrimSpace(line[i+2:])
+		}
+		fields := strings.Fields(requirement)
+		if len(fields) != 2 || isIndirectComment(comment) {
+			continue
+		}
+		direct[strings.Trim(fields[0], `"`)] = true
+	}
+	return direct, scanner.Err()
+}
+
+// isIndirectComment returns true for the `// indirect` comment, which may be followed by other comments, e.g. `// indirect; reason`
+func isIndirectComment(comment string) bool {
+	return comment == "indirect" || strings.HasPrefix(comment, "indirect;")
+}
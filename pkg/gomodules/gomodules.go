@@ -0,0 +1,181 @@
+package gomodules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Module is the description of a go module as reported by `go list -m -json`
+type Module struct {
+	Path     string  `json:"Path"`
+	Version  string  `json:"Version,omitempty"`
+	Dir      string  `json:"Dir,omitempty"`
+	Main     bool    `json:"Main,omitempty"`
+	Indirect bool    `json:"Indirect,omitempty"`
+	Replace  *Module `json:"Replace,omitempty"`
+	Sum      string  `json:"Sum,omitempty"`
+}
+
+// List returns all the go modules the project in the given directory depends on, including the main module.
+// An empty directory means the current directory.
+func List(dir string) ([]Module, error) {
+	out, err := goCommand(dir, "list", "-m", "-json", "all")
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []Module
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var module Module
+		if err := decoder.Decode(&module); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to parse go modules list: %v", err)
+		}
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
+
+// PackageModules returns the paths of the given modules which provide packages to the build of the project in the given
+// directory, i.e. to its packages and their dependencies, including the modules which have not been downloaded.
+// Modules are only looked up in the module cache, so that listing the packages never downloads them.
+// An empty directory means the current directory.
+func PackageModules(dir string, modules []Module) (map[string]bool, error) {
+	out, err := goCommandWithEnv(dir, []string{"GOPROXY=off"}, "list", "-deps", "-e", "-f", "{{if not .Standard}}{{.ImportPath}}{{end}}", "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	packageModules := make(map[string]bool)
+	for _, importPath := range strings.Fields(string(out)) {
+		// packages whose module has not been downloaded have no module, so they belong to the module with the longest
+		// path prefixing their import path
+		var providing string
+		for _, module := range modules {
+			if (importPath == module.Path || strings.HasPrefix(importPath, module.Path+"/")) && len(module.Path) > len(providing) {
+				providing = module.Path
+			}
+		}
+		if providing != "" {
+			packageModules[providing] = true
+		}
+	}
+	return packageModules, nil
+}
+
+// Dir returns the directory of the given module, which the project in the current directory depends on
+func Dir(path string) (string, error) {
+	out, err := goCommand("", "list", "-m", "-f", "{{.Dir}}", path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// String returns the identity of the module, i.e. its path and version when it has one
+func (m *Module) String() string {
+	if m.Version == "" {
+		return m.Path
+	}
+	return m.Path + "@" + m.Version
+}
+
+// IsLocalReplacement returns true when the replacement of a module is a directory rather than another module
+func (m *Module) IsLocalReplacement() bool {
+	return m.Replace != nil && m.Replace.Version == ""
+}
+
+// CacheDir returns the directory where the given module version is extracted in the local module cache.
+// The directory is only returned when the module has already been downloaded.
+func CacheDir(path, version string) (string, error) {
+	modCache, err := ModCache()
+	if err != nil {
+		return "", err
+	}
+
+	escapedPath, err := escape(path)
+	if err != nil {
+		return "", err
+	}
+	escapedVersion, err := escape(version)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(modCache, filepath.FromSlash(escapedPath)+"@"+escapedVersion)
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("module %s@%s not found in module cache: %v", path, version, err)
+	}
+	return dir, nil
+}
+
+// ModCache returns the root directory of the local module cache
+func ModCache() (string, error) {
+	if modCache := os.Getenv("GOMODCACHE"); modCache != "" {
+		return modCache, nil
+	}
+
+	// GOMODCACHE is only reported by `go env` from go 1.15, fall back on GOPATH for older versions
+	out, err := goCommand("", "env", "GOMODCACHE", "GOPATH")
+	if err != nil {
+		return "", err
+	}
+	values := strings.Split(string(out), "\n")
+	if modCache := strings.TrimSpace(values[0]); modCache != "" {
+		return modCache, nil
+	}
+	for _, value := range values[1:] {
+		if gopath := strings.TrimSpace(value); gopath != "" {
+			return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod"), nil
+		}
+	}
+	return "", fmt.Errorf("unable to find the go module cache: neither GOMODCACHE nor GOPATH are set")
+}
+
+// escape applies the module cache case-encoding, where each upper case letter is replaced by '!' followed by the letter in lower case
+func escape(s string) (string, error) {
+	var b strings.Builder
+	for _, r := range s {
+		if r == '!' || r >= unicode.MaxASCII {
+			return "", fmt.Errorf("invalid character %q in %q", r, s)
+		}
+		if unicode.IsUpper(r) {
+			b.WriteRune('!')
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), nil
+}
+
+func goCommand(dir string, args ...string) ([]byte, error) {
+	return goCommandWithEnv(dir, nil, args...)
+}
+
+// goCommandWithEnv runs the go command with the given environment variables in addition to the environment
+func goCommandWithEnv(dir string, env []string, args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s %s (try setting GO111MODULE=on)", strings.TrimSpace(stderr.String()), err)
+	}
+	return out.Bytes(), nil
+}
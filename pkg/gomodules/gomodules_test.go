@@ -0,0 +1,328 @@
+package gomodules
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestGoModules(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/gomodules.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Go Modules Suite", []Reporter{junitReporter})
+}
+
+var _ = Describe("go modules", func() {
+
+	BeforeEach(func() {
+		setEnv("GO111MODULE", "on")
+		setEnv("GOFLAGS", "")
+		setEnv("GOPROXY", "off")
+	})
+
+	AfterEach(func() {
+		for key, value := range previousEnv {
+			os.Setenv(key, value)
+			delete(previousEnv, key)
+		}
+	})
+
+	It("should list the main module and its replaced dependencies", func() {
+		// when
+		modules, err := List("testdata/replaced-module")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(modules).To(HaveLen(2))
+		Expect(modules[0].Path).To(Equal("example.com/replaced-module"))
+		Expect(modules[0].Main).To(BeTrue())
+		Expect(modules[1].Path).To(Equal("github.com/Some-Org/original"))
+		Expect(modules[1].Version).To(Equal("v1.0.0"))
+		Expect(modules[1].Dir).To(HaveSuffix(filepath.Join("testdata", "replaced-module", "fork")))
+		Expect(modules[1].Replace).ToNot(BeNil())
+		Expect(modules[1].Replace.Path).To(Equal("./fork"))
+		Expect(modules[1].IsLocalReplacement()).To(BeTrue())
+	})
+
+	It("should fail to list modules outside of a go module", func() {
+		// when
+		_, err := List("testdata/modcache")
+
+		// then
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("module cache", func() {
+		BeforeEach(func() {
+			setEnv("GOMODCACHE", "testdata/modcache")
+		})
+
+		It("should find the directory of a downloaded module using the case-encoded path", func() {
+			// when
+			dir, err := CacheDir("github.com/Some-Org/original", "v1.0.0")
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dir).To(Equal(filepath.Join("testdata", "modcache", "github.com", "!some-!org", "original@v1.0.0")))
+		})
+
+		It("should fail when the module has not been downloaded", func() {
+			// when
+			_, err := CacheDir("github.com/Some-Org/original", "v2.0.0")
+
+			// then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should find the zip of a downloaded module", func() {
+			// when
+			zipPath, err := CacheZip("github.com/Some-Org/original", "v1.0.0")
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(zipPath).To(Equal(filepath.Join("testdata", "modcache", "cache", "download", "github.com", "!some-!org", "original", "@v", "v1.0.0.zip")))
+		})
+
+		It("should fail when the module zip has not been downloaded", func() {
+			// when
+			_, err := CacheZip("github.com/Some-Org/original", "v2.0.0")
+
+			// then
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("proxies", func() {
+		var fileProxy string
+		var downloadDir string
+
+		BeforeEach(func() {
+			proxyDir, err := filepath.Abs("testdata/modcache/cache/download")
+			Expect(err).ToNot(HaveOccurred())
+			fileProxy = "file://" + filepath.ToSlash(proxyDir)
+
+			downloadDir, err = ioutil.TempDir("", "gomodules-test")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(downloadDir)
+		})
+
+		It("should list the file and http proxies of GOPROXY", func() {
+			// given
+			setEnv("GOPROXY", "https://proxy.golang.org,file:///var/goproxy|http://localhost:3000/,direct")
+
+			// when
+			proxies := Proxies()
+
+			// then
+			Expect(proxies).To(Equal([]string{"https://proxy.golang.org", "file:///var/goproxy", "http://localhost:3000"}))
+		})
+
+		It("should find a module zip in the first file proxy that has it", func() {
+			// when
+			zipPath, err := ProxyZip([]string{"file:///does-not-exist", fileProxy}, downloadDir, "github.com/Some-Org/original", "v1.0.0")
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(zipPath).To(HaveSuffix(filepath.Join("testdata", "modcache", "cache", "download", "github.com", "!some-!org", "original", "@v", "v1.0.0.zip")))
+		})
+
+		It("should download a module zip from an http proxy", func() {
+			// given
+			server := httptest.NewServer(http.FileServer(http.Dir("testdata/modcache/cache/download")))
+			defer server.Close()
+
+			// when
+			zipPath, err := ProxyZip([]string{server.URL}, downloadDir, "github.com/Some-Org/original", "v1.0.0")
+			_, notFoundErr := ProxyZip([]string{server.URL}, downloadDir, "github.com/Some-Org/original", "v2.0.0")
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(zipPath).To(Equal(filepath.Join(downloadDir, "github.com", "!some-!org", "original", "@v", "v1.0.0.zip")))
+			Expect(ZipHash(zipPath)).To(Equal("h1:bsYwlpgwpWy649e1KGd8oy0kj3RMxs7UH62LRvrqP7A="))
+			Expect(notFoundErr).To(HaveOccurred())
+			Expect(notFoundErr.Error()).To(ContainSubstring("404"))
+		})
+
+		It("should only find the download directory when downloading from an http proxy", func() {
+			// given
+			server := httptest.NewServer(http.FileServer(http.Dir("testdata/modcache/cache/download")))
+			defer server.Close()
+			setEnv("XDG_CACHE_HOME", "")
+			setEnv("HOME", "")
+
+			// when
+			zipPath, err := ProxyZip([]string{fileProxy}, "", "github.com/Some-Org/original", "v1.0.0")
+			_, downloadErr := ProxyZip([]string{server.URL}, "", "github.com/Some-Org/original", "v1.0.0")
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(zipPath).To(HaveSuffix(filepath.Join("original", "@v", "v1.0.0.zip")))
+			Expect(downloadErr).To(MatchError(ContainSubstring("unable to find a directory to download go modules into")))
+		})
+
+		It("should locate a module extracted in the module cache first", func() {
+			// given
+			setEnv("GOMODCACHE", "testdata/modcache")
+
+			// when
+			path, err := Locate("github.com/Some-Org/original", "v1.0.0", "", nil, downloadDir)
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal(filepath.Join("testdata", "modcache", "github.com", "!some-!org", "original@v1.0.0")))
+		})
+
+		It("should locate a module zip from a proxy and check its hash", func() {
+			// given
+			setEnv("GOMODCACHE", "testdata/does-not-exist")
+			proxies := []string{fileProxy}
+
+			// when
+			path, err := Locate("github.com/Some-Org/original", "v1.0.0", "h1:bsYwlpgwpWy649e1KGd8oy0kj3RMxs7UH62LRvrqP7A=", proxies, downloadDir)
+			_, mismatchErr := Locate("github.com/Some-Org/original", "v1.0.0", "h1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=", proxies, downloadDir)
+			_, notFoundErr := Locate("github.com/Some-Org/original", "v2.0.0", "", proxies, downloadDir)
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(HaveSuffix(filepath.Join("@v", "v1.0.0.zip")))
+			Expect(mismatchErr).To(HaveOccurred())
+			Expect(notFoundErr).To(HaveOccurred())
+		})
+	})
+
+	Context("go.sum", func() {
+		const zipPath = "testdata/modcache/cache/download/github.com/!some-!org/original/@v/v1.0.0.zip"
+
+		It("should read the module zip hashes", func() {
+			// when
+			goSum, err := ReadGoSum("testdata/go.sum")
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(goSum).To(Equal(GoSum{
+				"github.com/Some-Org/original@v1.0.0": "h1:bsYwlpgwpWy649e1KGd8oy0kj3RMxs7UH62LRvrqP7A=",
+				"github.com/Some-Org/original@v1.1.0": "h1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=",
+			}))
+		})
+
+		It("should verify a module zip matching its go.sum hash", func() {
+			// given
+			goSum, err := ReadGoSum("testdata/go.sum")
+			Expect(err).ToNot(HaveOccurred())
+
+			// when
+			err = goSum.VerifyZip(zipPath, "github.com/Some-Org/original", "v1.0.0")
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail to verify a module zip not matching its go.sum hash", func() {
+			// given
+			goSum := GoSum{"github.com/Some-Org/original@v1.0.0": "h1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}
+
+			// when
+			err := goSum.VerifyZip(zipPath, "github.com/Some-Org/original", "v1.0.0")
+
+			// then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not match go.sum hash"))
+		})
+
+		It("should fail to verify a module zip missing from go.sum", func() {
+			// given
+			goSum := GoSum{}
+
+			// when
+			err := goSum.VerifyZip(zipPath, "github.com/Some-Org/original", "v1.0.0")
+
+			// then
+			Expect(err).To(Equal(ErrMissingFromGoSum))
+		})
+	})
+
+	Context("go.mod", func() {
+		It("should read the direct requirements, leaving out the ones marked indirect", func() {
+			// when
+			direct, err := DirectRequirements("testdata/requirements/go.mod")
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(direct).To(Equal(map[string]bool{
+				"github.com/direct/single": true,
+				"github.com/direct/block":  true,
+				"github.com/direct/quoted": true,
+			}))
+		})
+
+		It("should fail when there is no go.mod", func() {
+			// when
+			_, err := DirectRequirements("testdata/does-not-exist/go.mod")
+
+			// then
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("vendored modules", func() {
+		It("should list the modules with vendored packages", func() {
+			// when
+			modules, err := VendoredModules("testdata/vendor")
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(modules).To(Equal([]Module{
+				{Path: "github.com/foo/bar", Version: "v1.2.3", Dir: filepath.Join("testdata", "vendor", "github.com", "foo", "bar")},
+				{Path: "example.com/replaced", Version: "v1.0.0", Dir: filepath.Join("testdata", "vendor", "example.com", "replaced"),
+					Replace: &Module{Path: "github.com/fork/replaced", Version: "v1.0.1"}},
+			}))
+		})
+
+		It("should fail when there is no modules.txt", func() {
+			// when
+			_, err := VendoredModules("testdata/modcache")
+
+			// then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should find vendor directories not belonging to any module", func() {
+			// given
+			modules, err := VendoredModules("testdata/vendor")
+			Expect(err).ToNot(HaveOccurred())
+
+			// when
+			dirs, err := UnlistedVendorDirs("testdata/vendor", modules)
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dirs).To(Equal([]string{filepath.Join("testdata", "vendor", "github.com", "unlisted", "pkg")}))
+		})
+	})
+})
+
+var previousEnv = make(map[string]string)
+
+func setEnv(key, value string) {
+	if _, saved := previousEnv[key]; !saved {
+		previousEnv[key] = os.Getenv(key)
+	}
+	os.Setenv(key, value)
+}
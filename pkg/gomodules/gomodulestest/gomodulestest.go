@@ -0,0 +1,20 @@
+// Package gomodulestest provides utilities to test the reading of go modules
+package gomodulestest
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// BuildBinary builds the go executable of the directory offline into the binary path, with the given environment.
+// Building requires go 1.18 or later, which embeds the build information of executables read by the tests.
+func BuildBinary(dir, binary string, env ...string) (string, error) {
+	cmd := exec.Command("go", "build", "-buildvcs=false", "-o", binary, ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), append([]string{"GOPROXY=off"}, env...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("unable to build %s: %v: %s", dir, err, output)
+	}
+	return binary, nil
+}
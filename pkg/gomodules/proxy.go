@@ -0,0 +1,155 @@
+package gomodules
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// proxyClient is used to download module zips from HTTP proxies
+var proxyClient = &http.Client{Timeout: 2 * time.Minute}
+
+// Proxies returns the proxy URLs listed in the GOPROXY environment variable, i.e. `file://` or `http(s)://` URLs.
+// The `direct` and `off` keywords are not proxies, and are left out.
+func Proxies() []string {
+	var proxies []string
+	for _, proxy := range strings.FieldsFunc(os.Getenv("GOPROXY"), func(r rune) bool { return r == ',' || r == '|' }) {
+		proxyURL, err := url.Parse(strings.TrimSpace(proxy))
+		if err != nil {
+			continue
+		}
+		switch proxyURL.Scheme {
+		case "file", "http", "https":
+			proxies = append(proxies, strings.TrimSuffix(proxyURL.String(), "/"))
+		}
+	}
+	return proxies
+}
+
+// ProxyZip returns the path of the zip archive of the given module version from the first proxy that has it.
+// Zips from HTTP proxies are downloaded into the given download directory, or into DownloadDir when it is empty, with
+// the same layout as a file-based proxy.
+func ProxyZip(proxies []string, downloadDir string, path, version string) (string, error) {
+	zipFile, err := zipFileName(path, version)
+	if err != nil {
+		return "", err
+	}
+
+	var errs []string
+	for _, proxy := range proxies {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		if proxyURL.Scheme == "file" {
+			zipPath := filepath.Join(filepath.FromSlash(proxyURL.Path), zipFile)
+			if _, err := os.Stat(zipPath); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			return zipPath, nil
+		}
+
+		if downloadDir == "" {
+			if downloadDir, err = DownloadDir(); err != nil {
+				return "", fmt.Errorf("unable to find a directory to download go modules into: %v", err)
+			}
+		}
+		zipPath := filepath.Join(downloadDir, zipFile)
+		if _, err := os.Stat(zipPath); err == nil {
+			return zipPath, nil
+		}
+		if err := download(proxy+"/"+filepath.ToSlash(zipFile), zipPath); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		return zipPath, nil
+	}
+	return "", fmt.Errorf("module %s@%s not found in GOPROXY %v: %s", path, version, proxies, strings.Join(errs, "; "))
+}
+
+// Locate returns the directory or zip archive of the given module version, looking in turn into the module cache,
+// the module download cache and the given proxies, downloading zips into the download directory as with ProxyZip.
+// The hash of zip archives is checked against the expected `h1:` sum if any.
+func Locate(path, version, sum string, proxies []string, downloadDir string) (string, error) {
+	if dir, err := CacheDir(path, version); err == nil {
+		return dir, nil
+	}
+
+	zipPath, err := CacheZip(path, version)
+	if err != nil {
+		if zipPath, err = ProxyZip(proxies, downloadDir, path, version); err != nil {
+			return "", err
+		}
+	}
+
+	if sum != "" {
+		hash, err := ZipHash(zipPath)
+		if err != nil {
+			return "", err
+		}
+		if hash != sum {
+			return "", fmt.Errorf("module %s@%s zip hash %s does not match expected hash %s", path, version, hash, sum)
+		}
+	}
+	return zipPath, nil
+}
+
+// DownloadDir returns the directory where module zips downloaded from HTTP proxies are kept between runs
+func DownloadDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "licence-compliance-checker", "modules"), nil
+}
+
+// zipFileName returns the path of a module zip relative to the root of a proxy, i.e. `<module path>/@v/<version>.zip`
+func zipFileName(path, version string) (string, error) {
+	escapedPath, err := escape(path)
+	if err != nil {
+		return "", err
+	}
+	escapedVersion, err := escape(version)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.FromSlash(escapedPath), "@v", escapedVersion+".zip"), nil
+}
+
+func download(fromURL, toPath string) error {
+	resp, err := proxyClient.Get(fromURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", fromURL, resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(toPath), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(toPath), filepath.Base(toPath))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), toPath)
+}
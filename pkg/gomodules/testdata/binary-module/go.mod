@@ -0,0 +1,10 @@
+module example.com/binary-module
+
+go 1.17
+
+require (
+	example.com/dep v1.2.0
+	example.com/old v1.0.0
+)
+
+replace example.com/old => github.com/Some-Org/original v1.0.0
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"example.com/dep"
+	"example.com/old"
+)
+
+func main() {
+	dep.Run()
+	old.Run()
+}
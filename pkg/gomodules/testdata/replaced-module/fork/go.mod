@@ -0,0 +1,3 @@
+module github.com/Some-Org/original
+
+go 1.12
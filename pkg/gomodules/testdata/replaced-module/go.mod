@@ -0,0 +1,7 @@
+module example.com/replaced-module
+
+go 1.12
+
+require github.com/Some-Org/original v1.0.0
+
+replace github.com/Some-Org/original => ./fork
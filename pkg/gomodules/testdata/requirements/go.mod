@@ -0,0 +1,16 @@
+module example.com/requirements
+
+go 1.17
+
+require github.com/direct/single v1.0.0
+
+require (
+	github.com/direct/block v1.2.0 // pinned
+	"github.com/direct/quoted" v0.1.0
+	github.com/indirect/block v0.3.0 // indirect
+	github.com/indirect/reason v0.4.0 // indirect; needed by github.com/direct/block
+)
+
+require github.com/indirect/single v1.1.0 // indirect
+
+replace github.com/direct/block => github.com/fork/block v1.2.1
@@ -0,0 +1,123 @@
+package gomodules
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ModulesTxt is the name of the file listing the vendored go modules, as created by `go mod vendor`
+const ModulesTxt = "modules.txt"
+
+// VendoredModules returns the go modules listed in the `modules.txt` of the given vendor directory.
+// Only modules with vendored packages are returned, each with the directory it is vendored into.
+func VendoredModules(vendorDir string) ([]Module, error) {
+	f, err := os.Open(filepath.Join(vendorDir, ModulesTxt))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var modules []Module
+	var module *Module
+	var hasPackages bool
+	addModule := func() {
+		if module != nil && hasPackages {
+			modules = append(modules, *module)
+		}
+	}
+
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "##"):
+			// annotations such as `## explicit` do not affect where modules are vendored
+		case strings.HasPrefix(line, "# "):
+			addModule()
+			module, err = parseModuleLine(strings.TrimPrefix(line, "# "))
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", ModulesTxt, lineNumber, err)
+			}
+			module.Dir = filepath.Join(vendorDir, filepath.FromSlash(module.Path))
+			hasPackages = false
+		default:
+			if module == nil {
+				return nil, fmt.Errorf("%s:%d: package %s is not part of any module", ModulesTxt, lineNumber, line)
+			}
+			hasPackages = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	addModule()
+	return modules, nil
+}
+
+// parseModuleLine parses a module line of `modules.txt`, e.g. `path version` or `path version => replacement [version]`
+func parseModuleLine(line string) (*Module, error) {
+	parts := strings.SplitN(line, "=>", 2)
+	fields := strings.Fields(parts[0])
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid module line %q", line)
+	}
+
+	module := &Module{Path: fields[0]}
+	if len(fields) == 2 {
+		module.Version = fields[1]
+	}
+
+	if len(parts) == 2 {
+		replaceFields := strings.Fields(parts[1])
+		if len(replaceFields) == 0 || len(replaceFields) > 2 {
+			return nil, fmt.Errorf("invalid module replacement %q", line)
+		}
+		module.Replace = &Module{Path: replaceFields[0]}
+		if len(replaceFields) == 2 {
+			module.Replace.Version = replaceFields[1]
+		}
+	}
+	return module, nil
+}
+
+// UnlistedVendorDirs returns the directories of the vendor directory containing files, but not belonging to any of the given modules
+func UnlistedVendorDirs(vendorDir string, modules []Module) ([]string, error) {
+	moduleDirs := make(map[string]bool)
+	for _, module := range modules {
+		moduleDirs[filepath.Clean(module.Dir)] = true
+	}
+
+	unlisted := make(map[string]bool)
+	err := filepath.Walk(vendorDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if moduleDirs[filepath.Clean(path)] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		if filepath.Clean(dir) == filepath.Clean(vendorDir) {
+			return nil
+		}
+		unlisted[dir] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for dir := range unlisted {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
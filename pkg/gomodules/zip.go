@@ -0,0 +1,122 @@
+package gomodules
+
+import (
+	"archive/zip"
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrMissingFromGoSum is returned when verifying a module zip which has no hash in go.sum
+var ErrMissingFromGoSum = errors.New("module is missing from go.sum")
+
+// CacheZip returns the path of the zip archive of the given module version in the module download cache
+func CacheZip(path, version string) (string, error) {
+	modCache, err := ModCache()
+	if err != nil {
+		return "", err
+	}
+
+	zipFile, err := zipFileName(path, version)
+	if err != nil {
+		return "", err
+	}
+
+	zipPath := filepath.Join(modCache, "cache", "download", zipFile)
+	if _, err := os.Stat(zipPath); err != nil {
+		return "", fmt.Errorf("module %s@%s not found in module download cache: %v", path, version, err)
+	}
+	return zipPath, nil
+}
+
+// GoSum holds the hashes of the module zips listed in a go.sum file, keyed by module path and version
+type GoSum map[string]string
+
+// ReadGoSum reads the module zip hashes of the given go.sum file
+func ReadGoSum(path string) (GoSum, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	goSum := make(GoSum)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		goSum[fields[0]+"@"+fields[1]] = fields[2]
+	}
+	return goSum, scanner.Err()
+}
+
+// VerifyZip checks that the hash of the module zip matches the one recorded in go.sum for the given module version
+func (s GoSum) VerifyZip(zipPath, path, version string) error {
+	expected, ok := s[path+"@"+version]
+	if !ok {
+		return ErrMissingFromGoSum
+	}
+
+	actual, err := ZipHash(zipPath)
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return fmt.Errorf("module %s@%s zip hash %s does not match go.sum hash %s", path, version, actual, expected)
+	}
+	return nil
+}
+
+// ZipHash computes the `h1:` hash of a module zip, as recorded in go.sum.
+// It is the base64 encoded SHA-256 of the sorted list of SHA-256 and names of the files in the zip.
+func ZipHash(zipPath string) (string, error) {
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return "", err
+	}
+	defer archive.Close()
+
+	files := make(map[string]*zip.File)
+	var names []string
+	for _, f := range archive.File {
+		if strings.Contains(f.Name, "\n") {
+			return "", fmt.Errorf("invalid file name %q in %s", f.Name, zipPath)
+		}
+		files[f.Name] = f
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+
+	summary := sha256.New()
+	for _, name := range names {
+		fileHash, err := hashZipFile(files[name])
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(summary, "%x  %s\n", fileHash, name)
+	}
+	return "h1:" + base64.StdEncoding.EncodeToString(summary.Sum(nil)), nil
+}
+
+func hashZipFile(f *zip.File) ([]byte, error) {
+	reader, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, reader); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,187 @@
+package licencelist
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// cacheFile is the file of the cache directory holding the imported licence list
+const cacheFile = "licence-list.json"
+
+// List is an SPDX licence list imported from a license-list-data bundle. It only holds the licences which are unknown to
+// the licence database embedded in go-license-detector.
+type List struct {
+	Version     string    `json:"licenseListVersion"`
+	ReleaseDate string    `json:"releaseDate,omitempty"`
+	Licences    []Licence `json:"licenses"`
+}
+
+// Licence is a licence of the SPDX licence list, with its text
+type Licence struct {
+	ID   string `json:"licenseId"`
+	Name string `json:"name,omitempty"`
+	Text string `json:"text"`
+}
+
+// bundleLicences is the json/licenses.json file of license-list-data bundles
+type bundleLicences struct {
+	Version     string `json:"licenseListVersion"`
+	ReleaseDate string `json:"releaseDate"`
+	Licences    []struct {
+		ID         string `json:"licenseId"`
+		Name       string `json:"name"`
+		Deprecated bool   `json:"isDeprecatedLicenseId"`
+	} `json:"licenses"`
+}
+
+// bundleDetails is a json/details/<licence>.json file of license-list-data bundles
+type bundleDetails struct {
+	Text string `json:"licenseText"`
+}
+
+// CacheDir returns the default directory where the imported licence list is kept between runs
+func CacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "licence-compliance-checker", "licence-list"), nil
+}
+
+// Import reads the SPDX licence list of a license-list-data bundle directory, i.e. its json/licenses.json file and the
+// licence texts of its text directory or json/details files, and writes the licences which are neither deprecated nor
+// known to the embedded licence database into the cache directory, replacing any previously imported list.
+func Import(bundleDir, cacheDir string) (*List, error) {
+	jsonDir := filepath.Join(bundleDir, "json")
+	if _, err := os.Stat(filepath.Join(jsonDir, "licenses.json")); err != nil {
+		jsonDir = bundleDir
+	}
+	content, err := ioutil.ReadFile(filepath.Join(jsonDir, "licenses.json"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the licence list bundle: %v", err)
+	}
+	var bundle bundleLicences
+	if err := json.Unmarshal(content, &bundle); err != nil {
+		return nil, fmt.Errorf("unable to parse the licence list bundle: %v", err)
+	}
+	if bundle.Version == "" {
+		return nil, fmt.Errorf("unable to parse the licence list bundle: no licenseListVersion")
+	}
+
+	list := &List{Version: bundle.Version, ReleaseDate: bundle.ReleaseDate}
+	for _, bundleLicence := range bundle.Licences {
+		if bundleLicence.Deprecated {
+			continue
+		}
+		text, err := licenceText(bundleDir, jsonDir, bundleLicence.ID)
+		if err != nil {
+			log.Warnf("Licence %s of the licence list is not imported: %v", bundleLicence.ID, err)
+			continue
+		}
+		if embeddedLicence(bundleLicence.ID, text) {
+			log.Debugf("Licence %s of the licence list is already known", bundleLicence.ID)
+			continue
+		}
+		list.Licences = append(list.Licences, Licence{ID: bundleLicence.ID, Name: bundleLicence.Name, Text: text})
+	}
+
+	if err := list.write(cacheDir); err != nil {
+		return nil, fmt.Errorf("unable to write the licence list cache: %v", err)
+	}
+	return list, nil
+}
+
+// licenceText returns the text of a licence of a bundle, from text/<licence>.txt or json/details/<licence>.json
+func licenceText(bundleDir, jsonDir, licence string) (string, error) {
+	if text, err := ioutil.ReadFile(filepath.Join(bundleDir, "text", licence+".txt")); err == nil {
+		return string(text), nil
+	}
+	content, err := ioutil.ReadFile(filepath.Join(jsonDir, "details", licence+".json"))
+	if err != nil {
+		return "", fmt.Errorf("no licence text")
+	}
+	var details bundleDetails
+	if err := json.Unmarshal(content, &details); err != nil {
+		return "", err
+	}
+	if details.Text == "" {
+		return "", fmt.Errorf("no licence text")
+	}
+	return details.Text, nil
+}
+
+// embeddedLicence returns true when the embedded licence database identifies the text as the licence
+func embeddedLicence(licence, text string) bool {
+	for _, match := range detection.DetectLicenceText([]byte(text)) {
+		if match.Licence == licence {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *List) write(cacheDir string) error {
+	content, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(cacheDir, cacheFile)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(cacheDir, cacheFile))
+}
+
+// Load reads the licence list imported into the cache directory, or returns nil when no licence list has been imported
+func Load(cacheDir string) (*List, error) {
+	content, err := ioutil.ReadFile(filepath.Join(cacheDir, cacheFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list List
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("unable to parse the licence list cache %s: %v", filepath.Join(cacheDir, cacheFile), err)
+	}
+	return &list, nil
+}
+
+// Templates returns the licence templates of the licences of the list, to match them alongside the embedded licences
+func (l *List) Templates() ([]detection.LicenceTemplate, error) {
+	var templates []detection.LicenceTemplate
+	for _, licence := range l.Licences {
+		template, err := detection.NewLicenceTemplate(licence.ID, licence.Text)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+// IDs returns the identifiers of the licences of the list
+func (l *List) IDs() []string {
+	var ids []string
+	for _, licence := range l.Licences {
+		ids = append(ids, licence.ID)
+	}
+	return ids
+}
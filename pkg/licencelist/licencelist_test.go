@@ -0,0 +1,89 @@
+package licencelist
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestLicenceList(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/licencelist.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Licence List Suite", []Reporter{junitReporter})
+}
+
+var _ = Describe("licence list", func() {
+	var cacheDir string
+
+	BeforeEach(func() {
+		var err error
+		cacheDir, err = ioutil.TempDir("", "licencelist")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(cacheDir)
+	})
+
+	It("should import the licences unknown to the embedded licence database", func() {
+		// when
+		list, err := Import("testdata/license-list-data", cacheDir)
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list.Version).To(Equal("3.99"))
+		Expect(list.ReleaseDate).To(Equal("2026-09-30"))
+		Expect(list.Licences).To(HaveLen(2))
+		Expect(list.Licences[0].ID).To(Equal("Example-New-1.0"))
+		Expect(list.Licences[0].Name).To(Equal("Example New Licence 1.0"))
+		Expect(list.Licences[0].Text).To(HavePrefix("Example New Licence, version 1.0"))
+		Expect(list.Licences[1].ID).To(Equal("Example-Details-1.0"))
+		Expect(list.Licences[1].Text).To(HavePrefix("Example Details Licence 1.0"))
+	})
+
+	It("should load the imported licence list", func() {
+		// given
+		imported, err := Import("testdata/license-list-data", cacheDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		// when
+		list, err := Load(cacheDir)
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(Equal(imported))
+		templates, err := list.Templates()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(templates).To(HaveLen(2))
+		Expect(templates[0].Licence).To(Equal("Example-New-1.0"))
+		Expect(list.IDs()).To(Equal([]string{"Example-New-1.0", "Example-Details-1.0"}))
+	})
+
+	It("should load no licence list when none has been imported", func() {
+		// when
+		list, err := Load(cacheDir)
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(BeNil())
+	})
+
+	It("should fail when the bundle has no licence list", func() {
+		// when
+		_, err := Import("testdata", cacheDir)
+
+		// then
+		Expect(err).To(MatchError(ContainSubstring("unable to read the licence list bundle")))
+	})
+})
@@ -0,0 +1,306 @@
+package maven
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxParents limits how many parent POMs are read, in case of a cycle
+const maxParents = 10
+
+// propertyRe matches the property references of POM values, e.g. ${project.version}
+var propertyRe = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// Artifact is the description of a maven artifact a project depends on
+type Artifact struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Type       string
+	Classifier string
+	// Scope is the maven scope of the dependency, e.g. compile or test
+	Scope string
+}
+
+// String returns the identity of the artifact, i.e. groupId:artifactId:version
+func (a *Artifact) String() string {
+	return a.GroupID + ":" + a.ArtifactID + ":" + a.Version
+}
+
+// Key returns the identity of the artifact regardless of its version, i.e. groupId:artifactId
+func (a *Artifact) Key() string {
+	return a.GroupID + ":" + a.ArtifactID
+}
+
+// Dir returns the directory of the artifact version in the given local repository
+func (a *Artifact) Dir(repository string) string {
+	return filepath.Join(repository, filepath.FromSlash(strings.Replace(a.GroupID, ".", "/", -1)), a.ArtifactID, a.Version)
+}
+
+// File returns the path of the artifact file in the given local repository, e.g. the jar of a jar artifact
+func (a *Artifact) File(repository string) string {
+	name := a.ArtifactID + "-" + a.Version
+	if a.Classifier != "" {
+		name += "-" + a.Classifier
+	}
+	extension := a.Type
+	if extension == "" || extension == "bundle" {
+		extension = "jar"
+	}
+	return filepath.Join(a.Dir(repository), name+"."+extension)
+}
+
+// Pom returns the path of the POM of the artifact in the given local repository
+func (a *Artifact) Pom(repository string) string {
+	return filepath.Join(a.Dir(repository), a.ArtifactID+"-"+a.Version+".pom")
+}
+
+// LocalRepository returns the default local repository, i.e. ~/.m2/repository
+func LocalRepository() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".m2", "repository"), nil
+}
+
+// ReadDependencyList reads the artifacts of a dependency list, as written by `mvn dependency:list -DoutputFile=<file>`.
+// Each artifact is either `groupId:artifactId:version` or `groupId:artifactId:type[:classifier]:version:scope`, and other
+// lines are skipped.
+func ReadDependencyList(path string) ([]Artifact, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var artifacts []Artifact
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "[INFO]"))
+		// recent maven versions append the java module name, e.g. `-- module org.slf4j`
+		if i := strings.Index(line, " "); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Split(line, ":")
+		switch len(fields) {
+		case 3:
+			artifacts = append(artifacts, Artifact{GroupID: fields[0], ArtifactID: fields[1], Version: fields[2]})
+		case 5:
+			artifacts = append(artifacts, Artifact{GroupID: fields[0], ArtifactID: fields[1], Type: fields[2], Version: fields[3], Scope: fields[4]})
+		case 6:
+			artifacts = append(artifacts, Artifact{GroupID: fields[0], ArtifactID: fields[1], Type: fields[2], Classifier: fields[3], Version: fields[4], Scope: fields[5]})
+		}
+	}
+	return artifacts, scanner.Err()
+}
+
+// ReadPomDependencies reads the direct dependencies of the given POM.
+// Their versions are resolved from the properties and dependency management of the POM and of its parent POMs, which
+// are read from the local repository. Dependencies whose version cannot be resolved are returned without version.
+func ReadPomDependencies(pomPath string, repository string) ([]Artifact, error) {
+	project, err := readPom(pomPath)
+	if err != nil {
+		return nil, err
+	}
+	effective, err := withParents(project, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	managedVersions := make(map[string]string)
+	for _, dependency := range effective.managedDependencies {
+		managedVersions[dependency.GroupID+":"+dependency.ArtifactID] = effective.interpolate(dependency.Version)
+	}
+
+	var artifacts []Artifact
+	for _, dependency := range project.Dependencies {
+		artifact := Artifact{
+			GroupID:    effective.interpolate(dependency.GroupID),
+			ArtifactID: effective.interpolate(dependency.ArtifactID),
+			Version:    effective.interpolate(dependency.Version),
+			Type:       dependency.Type,
+			Classifier: dependency.Classifier,
+			Scope:      dependency.Scope,
+		}
+		if artifact.Version == "" {
+			artifact.Version = managedVersions[artifact.Key()]
+		}
+		if propertyRe.MatchString(artifact.Version) {
+			artifact.Version = ""
+		}
+		if artifact.Scope == "" {
+			artifact.Scope = "compile"
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, nil
+}
+
+// DeclaredLicences returns the names of the licences declared by the `<licenses>` of the artifact POM in the given
+// local repository, or by its closest parent POM declaring licences
+func DeclaredLicences(artifact Artifact, repository string) ([]string, error) {
+	pomPath := artifact.Pom(repository)
+	for i := 0; i < maxParents; i++ {
+		project, err := readPom(pomPath)
+		if err != nil {
+			return nil, err
+		}
+
+		var licences []string
+		for _, licence := range project.Licenses {
+			if name := strings.TrimSpace(licence.Name); name != "" {
+				licences = append(licences, name)
+			}
+		}
+		if len(licences) > 0 || project.Parent == nil {
+			return licences, nil
+		}
+
+		parent := Artifact{GroupID: project.Parent.GroupID, ArtifactID: project.Parent.ArtifactID, Version: project.Parent.Version}
+		pomPath = parent.Pom(repository)
+		if _, err := os.Stat(pomPath); err != nil {
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
+
+type pom struct {
+	GroupID              string       `xml:"groupId"`
+	ArtifactID           string       `xml:"artifactId"`
+	Version              string       `xml:"version"`
+	Parent               *pomParent   `xml:"parent"`
+	Properties           properties   `xml:"properties"`
+	Dependencies         []dependency `xml:"dependencies>dependency"`
+	DependencyManagement []dependency `xml:"dependencyManagement>dependencies>dependency"`
+	Licenses             []struct {
+		Name string `xml:"name"`
+	} `xml:"licenses>license"`
+}
+
+type pomParent struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+type dependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Type       string `xml:"type"`
+	Classifier string `xml:"classifier"`
+	Scope      string `xml:"scope"`
+}
+
+// properties are the `<properties>` of a POM, whose element names are the property names
+type properties map[string]string
+
+func (p *properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*p = make(properties)
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &element); err != nil {
+				return err
+			}
+			(*p)[element.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// effectivePom holds the properties and dependency management of a POM merged with the ones of its parents
+type effectivePom struct {
+	properties          map[string]string
+	managedDependencies []dependency
+}
+
+func readPom(path string) (*pom, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var project pom
+	if err := xml.NewDecoder(f).Decode(&project); err != nil {
+		return nil, fmt.Errorf("unable to parse POM %s: %v", path, err)
+	}
+	return &project, nil
+}
+
+func withParents(project *pom, repository string) (*effectivePom, error) {
+	effective := &effectivePom{properties: make(map[string]string)}
+	current := project
+	for i := 0; current != nil && i < maxParents; i++ {
+		for name, value := range current.Properties {
+			if _, ok := effective.properties[name]; !ok {
+				effective.properties[name] = value
+			}
+		}
+		effective.managedDependencies = append(effective.managedDependencies, current.DependencyManagement...)
+
+		groupID, version := current.GroupID, current.Version
+		if current.Parent != nil {
+			if groupID == "" {
+				groupID = current.Parent.GroupID
+			}
+			if version == "" {
+				version = current.Parent.Version
+			}
+		}
+		if current == project {
+			effective.properties["project.groupId"] = groupID
+			effective.properties["project.artifactId"] = current.ArtifactID
+			effective.properties["project.version"] = version
+			if current.Parent != nil {
+				effective.properties["project.parent.version"] = current.Parent.Version
+			}
+		}
+
+		if current.Parent == nil {
+			break
+		}
+		parent := Artifact{GroupID: current.Parent.GroupID, ArtifactID: current.Parent.ArtifactID, Version: current.Parent.Version}
+		parentPom, err := readPom(parent.Pom(repository))
+		if os.IsNotExist(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		current = parentPom
+	}
+	return effective, nil
+}
+
+// interpolate replaces the property references of the value with the property values, when they are known
+func (e *effectivePom) interpolate(value string) string {
+	value = strings.TrimSpace(value)
+	for i := 0; i < maxParents && propertyRe.MatchString(value); i++ {
+		value = propertyRe.ReplaceAllStringFunc(value, func(reference string) string {
+			name := propertyRe.FindStringSubmatch(reference)[1]
+			if name == "version" {
+				name = "project.version"
+			}
+			if propertyValue, ok := e.properties[name]; ok {
+				return propertyValue
+			}
+			return reference
+		})
+	}
+	return value
+}
@@ -0,0 +1,78 @@
+package maven
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"path/filepath"
+	"testing"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestMaven(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/maven.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Maven Suite", []Reporter{junitReporter})
+}
+
+const repository = "testdata/repository"
+
+var _ = Describe("maven artifacts", func() {
+
+	It("should locate artifacts in the local repository layout", func() {
+		artifact := Artifact{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "1.7.36"}
+		Expect(artifact.String()).To(Equal("org.slf4j:slf4j-api:1.7.36"))
+		Expect(artifact.File("repo")).To(Equal(filepath.Join("repo", "org", "slf4j", "slf4j-api", "1.7.36", "slf4j-api-1.7.36.jar")))
+		Expect(artifact.Pom("repo")).To(Equal(filepath.Join("repo", "org", "slf4j", "slf4j-api", "1.7.36", "slf4j-api-1.7.36.pom")))
+
+		artifact.Classifier = "tests"
+		Expect(artifact.File("repo")).To(Equal(filepath.Join("repo", "org", "slf4j", "slf4j-api", "1.7.36", "slf4j-api-1.7.36-tests.jar")))
+	})
+
+	It("should read the dependencies of a POM, resolving their versions from properties and parents", func() {
+		// when
+		artifacts, err := ReadPomDependencies("testdata/pom.xml", repository)
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(artifacts).To(Equal([]Artifact{
+			{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "1.7.36", Scope: "compile"},
+			{GroupID: "org.example", ArtifactID: "child-lib", Version: "1.2", Scope: "compile"},
+			{GroupID: "com.acme", ArtifactID: "managed", Version: "2.0", Classifier: "tests", Scope: "test"},
+			{GroupID: "org.unknown", ArtifactID: "unmanaged", Scope: "compile"},
+		}))
+	})
+
+	It("should read a dependency list", func() {
+		// when
+		artifacts, err := ReadDependencyList("testdata/dependencies.txt")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(artifacts).To(Equal([]Artifact{
+			{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Type: "jar", Version: "1.7.36", Scope: "compile"},
+			{GroupID: "com.acme", ArtifactID: "managed", Type: "jar", Classifier: "tests", Version: "2.0", Scope: "test"},
+			{GroupID: "org.example", ArtifactID: "child-lib", Type: "pom", Version: "1.2", Scope: "runtime"},
+			{GroupID: "org.example", ArtifactID: "parent", Version: "1.0"},
+		}))
+	})
+
+	It("should read the declared licences from the POM, or else from its parents", func() {
+		Expect(DeclaredLicences(Artifact{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "1.7.36"}, repository)).
+			To(Equal([]string{"MIT License", "Apache License, Version 2.0"}))
+		Expect(DeclaredLicences(Artifact{GroupID: "org.example", ArtifactID: "child-lib", Version: "1.2"}, repository)).
+			To(Equal([]string{"Apache License, Version 2.0"}))
+	})
+
+	It("should fail to read the declared licences of an artifact without POM", func() {
+		_, err := DeclaredLicences(Artifact{GroupID: "com.acme", ArtifactID: "managed", Version: "2.0"}, repository)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,200 @@
+package npm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PackageLock is the name of the file locking the packages of an npm project
+const PackageLock = "package-lock.json"
+
+const nodeModules = "node_modules/"
+
+// Package is the description of an npm package installed for a project
+type Package struct {
+	Name    string
+	Version string
+	// Dir is the directory the package is installed into
+	Dir string
+	// License is the licence declared by the package, usually an SPDX expression
+	License string
+	// Dev is true for packages only needed for development, i.e. not installed with `npm install --production`
+	Dev bool
+	// Optional is true for packages which may not be installed, e.g. platform specific packages
+	Optional bool
+	// Direct is true for packages the project itself depends on
+	Direct bool
+}
+
+// String returns the identity of the package, i.e. its name and version
+func (p *Package) String() string {
+	if p.Version == "" {
+		return p.Name
+	}
+	return p.Name + "@" + p.Version
+}
+
+// Installed returns true when the package has been installed into its directory
+func (p *Package) Installed() bool {
+	info, err := os.Stat(p.Dir)
+	return err == nil && info.IsDir()
+}
+
+type packageLock struct {
+	LockfileVersion int                    `json:"lockfileVersion"`
+	Packages        map[string]lockPackage `json:"packages"`
+}
+
+type lockPackage struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Resolved             string            `json:"resolved"`
+	Link                 bool              `json:"link"`
+	Dev                  bool              `json:"dev"`
+	Optional             bool              `json:"optional"`
+	License              json.RawMessage   `json:"license"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+type packageJSON struct {
+	License  json.RawMessage   `json:"license"`
+	Licenses []json.RawMessage `json:"licenses"`
+}
+
+// Packages returns the packages locked in the `package-lock.json` of the given project directory, sorted by name.
+// Only lock files from npm 7 onwards, i.e. lockfileVersion 2 or 3, list the packages with their install location.
+// The declared licence of a package is read from its `package.json` when the lock file does not have it.
+func Packages(projectDir string) ([]Package, error) {
+	content, err := ioutil.ReadFile(filepath.Join(projectDir, PackageLock))
+	if err != nil {
+		return nil, err
+	}
+
+	var lock packageLock
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", PackageLock, err)
+	}
+	if lock.LockfileVersion < 2 || lock.Packages == nil {
+		return nil, fmt.Errorf("unsupported %s lockfileVersion %d: regenerate it with npm 7 or later", PackageLock, lock.LockfileVersion)
+	}
+
+	root := lock.Packages[""]
+	var packages []Package
+	for location, lockPkg := range lock.Packages {
+		if location == "" || !strings.HasPrefix(location, nodeModules) && !strings.Contains(location, "/"+nodeModules) {
+			// the root project and workspace packages are not dependencies
+			continue
+		}
+
+		pkg := Package{
+			Name:     lockPkg.Name,
+			Version:  lockPkg.Version,
+			Dir:      filepath.Join(projectDir, filepath.FromSlash(location)),
+			Dev:      lockPkg.Dev,
+			Optional: lockPkg.Optional,
+		}
+		if pkg.Name == "" {
+			pkg.Name = location[strings.LastIndex(location, nodeModules)+len(nodeModules):]
+		}
+		if lockPkg.Link {
+			// linked packages are installed in the directory they resolve to, which is locked as a package of its own
+			pkg.Dir = filepath.Join(projectDir, filepath.FromSlash(lockPkg.Resolved))
+			if target, ok := lock.Packages[lockPkg.Resolved]; ok {
+				pkg.Version = target.Version
+				lockPkg.License = target.License
+			}
+		}
+		pkg.Direct = location == nodeModules+pkg.Name && root.dependsOn(pkg.Name)
+
+		if pkg.License, err = parseLicense(lockPkg.License); err != nil {
+			return nil, fmt.Errorf("%s: invalid license of %s: %v", PackageLock, location, err)
+		}
+		if pkg.License == "" {
+			if pkg.License, err = declaredLicense(pkg.Dir); err != nil {
+				return nil, err
+			}
+		}
+		packages = append(packages, pkg)
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name == packages[j].Name {
+			return packages[i].Dir < packages[j].Dir
+		}
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
+
+func (p lockPackage) dependsOn(name string) bool {
+	for _, dependencies := range []map[string]string{p.Dependencies, p.DevDependencies, p.OptionalDependencies, p.PeerDependencies} {
+		if _, ok := dependencies[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredLicense returns the licence declared in the `package.json` of the package directory, if any
+func declaredLicense(packageDir string) (string, error) {
+	content, err := ioutil.ReadFile(filepath.Join(packageDir, "package.json"))
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	var pkgJSON packageJSON
+	if err := json.Unmarshal(content, &pkgJSON); err != nil {
+		return "", fmt.Errorf("unable to parse %s: %v", filepath.Join(packageDir, "package.json"), err)
+	}
+
+	license, err := parseLicense(pkgJSON.License)
+	if err != nil || license != "" {
+		return license, err
+	}
+
+	// the deprecated `licenses` array lists alternative licences
+	var licenses []string
+	for _, l := range pkgJSON.Licenses {
+		license, err := parseLicense(l)
+		if err != nil {
+			return "", err
+		}
+		if license != "" {
+			licenses = append(licenses, license)
+		}
+	}
+	if len(licenses) > 1 {
+		return "(" + strings.Join(licenses, " OR ") + ")", nil
+	}
+	return strings.Join(licenses, ""), nil
+}
+
+// parseLicense parses a license field, which is usually an SPDX expression, or in older packages an object with a type
+func parseLicense(field json.RawMessage) (string, error) {
+	if len(field) == 0 || string(field) == "null" {
+		return "", nil
+	}
+
+	var license string
+	if err := json.Unmarshal(field, &license); err == nil {
+		return strings.TrimSpace(license), nil
+	}
+
+	var licenseObject struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(field, &licenseObject); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(licenseObject.Type), nil
+}
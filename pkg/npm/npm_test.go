@@ -0,0 +1,68 @@
+package npm
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"path/filepath"
+	"testing"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestNpm(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/npm.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Npm Suite", []Reporter{junitReporter})
+}
+
+var _ = Describe("npm packages", func() {
+
+	It("should list the packages of package-lock.json with their declared licence and scope", func() {
+		// when
+		packages, err := Packages("testdata/project")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(packages).To(Equal([]Package{
+			{Name: "@scope/nested", Version: "2.0.0", Dir: filepath.Join("testdata", "project", "node_modules", "mocha-lite", "node_modules", "@scope", "nested"), License: "(MIT OR Apache-2.0)", Dev: true},
+			{Name: "fsevents", Version: "2.3.2", Dir: filepath.Join("testdata", "project", "node_modules", "fsevents"), License: "MIT", Optional: true, Direct: true},
+			{Name: "left-pad", Version: "1.3.0", Dir: filepath.Join("testdata", "project", "node_modules", "left-pad"), License: "WTFPL", Direct: true},
+			{Name: "local", Version: "0.1.0", Dir: filepath.Join("testdata", "project", "packages", "local"), License: "ISC", Direct: true},
+			{Name: "mocha-lite", Version: "1.0.0", Dir: filepath.Join("testdata", "project", "node_modules", "mocha-lite"), License: "MIT", Dev: true, Direct: true},
+		}))
+	})
+
+	It("should find whether packages are installed", func() {
+		// when
+		packages, err := Packages("testdata/project")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(packages[1].String()).To(Equal("fsevents@2.3.2"))
+		Expect(packages[1].Installed()).To(BeFalse())
+		Expect(packages[2].Installed()).To(BeTrue())
+	})
+
+	It("should fail with a lock file from npm 6 or earlier", func() {
+		// when
+		_, err := Packages("testdata/v1")
+
+		// then
+		Expect(err).To(MatchError(ContainSubstring("unsupported package-lock.json lockfileVersion 1")))
+	})
+
+	It("should fail when there is no lock file", func() {
+		// when
+		_, err := Packages("testdata/does-not-exist")
+
+		// then
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,300 @@
+package ospackages
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Package managers whose installed packages are read from root filesystems
+const (
+	ManagerDpkg = "dpkg"
+	ManagerApk  = "apk"
+)
+
+// dep5Format is the prefix of the Format field of machine-readable debian copyright files, which is either http or https
+const dep5Format = "://www.debian.org/doc/packaging-manuals/copyright-format/1.0"
+
+// debianLicences maps the short names of licences commonly used by debian copyright files to their SPDX identifier
+var debianLicences = map[string]string{
+	"Expat":         "MIT",
+	"GPL-1":         "GPL-1.0-only",
+	"GPL-1+":        "GPL-1.0-or-later",
+	"GPL-2":         "GPL-2.0-only",
+	"GPL-2+":        "GPL-2.0-or-later",
+	"GPL-3":         "GPL-3.0-only",
+	"GPL-3+":        "GPL-3.0-or-later",
+	"LGPL-2":        "LGPL-2.0-only",
+	"LGPL-2+":       "LGPL-2.0-or-later",
+	"LGPL-2.1":      "LGPL-2.1-only",
+	"LGPL-2.1+":     "LGPL-2.1-or-later",
+	"LGPL-3":        "LGPL-3.0-only",
+	"LGPL-3+":       "LGPL-3.0-or-later",
+	"AGPL-3":        "AGPL-3.0-only",
+	"AGPL-3+":       "AGPL-3.0-or-later",
+	"BSD-2-clause":  "BSD-2-Clause",
+	"BSD-3-clause":  "BSD-3-Clause",
+	"BSD-4-clause":  "BSD-4-Clause",
+	"Apache-2":      "Apache-2.0",
+	"Apache-2.0":    "Apache-2.0",
+	"MPL-1.1":       "MPL-1.1",
+	"MPL-2.0":       "MPL-2.0",
+	"Artistic":      "Artistic-1.0-Perl",
+	"Zlib":          "Zlib",
+	"ISC":           "ISC",
+	"CC0-1.0":       "CC0-1.0",
+	"public-domain": "public-domain",
+	"GFDL-1.2+":     "GFDL-1.2-or-later",
+	"GFDL-1.3+":     "GFDL-1.3-or-later",
+}
+
+// Package is the description of an OS package installed in a root filesystem
+type Package struct {
+	Name    string
+	Version string
+	// Manager is the package manager which installed the package, i.e. dpkg or apk
+	Manager string
+	// Licences are the licences of the package, from its machine-readable copyright file for dpkg, or from the apk
+	// installed database. They are SPDX identifiers when known, or the names used by the package otherwise.
+	Licences []string
+	// CopyrightFile is the path of the copyright file of dpkg packages, relative to the root filesystem
+	CopyrightFile string
+}
+
+// String returns the identity of the package, i.e. its name and version
+func (p *Package) String() string {
+	return p.Name + "@" + p.Version
+}
+
+// DeclaredLicence returns the licences of the package as a single SPDX expression, all of them applying to a part of
+// the package
+func (p *Package) DeclaredLicence() string {
+	if len(p.Licences) > 1 {
+		return "(" + strings.Join(p.Licences, " AND ") + ")"
+	}
+	return strings.Join(p.Licences, "")
+}
+
+// Packages returns the OS packages installed in the given root filesystem, which is either an unpacked root filesystem
+// directory or a tarball, e.g. from `docker save`. Packages installed by dpkg are read from its status database, and
+// their licences from their machine-readable copyright files. Packages installed by apk are read from its installed
+// database.
+func Packages(rootfsPath string) ([]Package, error) {
+	fs, err := readRootFS(rootfsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	packages, err := dpkgPackages(fs)
+	if err != nil {
+		return nil, err
+	}
+	apkPackages, err := apkPackages(fs)
+	if err != nil {
+		return nil, err
+	}
+	packages = append(packages, apkPackages...)
+	if len(packages) == 0 {
+		return nil, fmt.Errorf("no dpkg or apk package database found in %s", rootfsPath)
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name == packages[j].Name {
+			return packages[i].Manager < packages[j].Manager
+		}
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
+
+func dpkgPackages(fs rootFS) ([]Package, error) {
+	// distroless images have a status file per package rather than a single database
+	statusFiles := fs.names(dpkgStatusDir)
+	if _, ok := fs[dpkgStatus]; ok {
+		statusFiles = append([]string{dpkgStatus}, statusFiles...)
+	}
+
+	var packages []Package
+	for _, statusFile := range statusFiles {
+		for _, stanza := range readStanzas(string(fs[statusFile])) {
+			name, version := stanza.value("Package"), stanza.value("Version")
+			if name == "" {
+				return nil, fmt.Errorf("%s: package without name", statusFile)
+			}
+			// packages removed but not purged stay in the database
+			if status := strings.Fields(stanza.value("Status")); len(status) > 0 && status[len(status)-1] != "installed" {
+				continue
+			}
+
+			// multi-arch packages have their architecture qualifier in their doc directory name only sometimes
+			pkg := Package{Name: name, Version: version, Manager: ManagerDpkg}
+			for _, docName := range []string{name, name + ":" + stanza.value("Architecture")} {
+				copyrightFile := docDir + docName + "/copyright"
+				if content, ok := fs[copyrightFile]; ok {
+					pkg.CopyrightFile = copyrightFile
+					pkg.Licences = copyrightLicences(string(content))
+					break
+				}
+			}
+			packages = append(packages, pkg)
+		}
+	}
+	return packages, nil
+}
+
+// copyrightLicences returns the licences of the Files paragraphs of a machine-readable debian copyright file (DEP-5), in
+// order of appearance. Copyright files which are not machine-readable have no licences.
+func copyrightLicences(content string) []string {
+	stanzas := readStanzas(content)
+	if len(stanzas) == 0 || !strings.Contains(stanzas[0].value("Format"), dep5Format) {
+		return nil
+	}
+
+	var licences []string
+	found := make(map[string]bool)
+	for i, stanza := range stanzas {
+		// the header paragraph may summarise the licence of the whole package, when there is no Files paragraph
+		if i > 0 && stanza.value("Files") == "" {
+			continue
+		}
+		for _, licence := range licenceNames(stanza.value("License")) {
+			if !found[licence] {
+				found[licence] = true
+				licences = append(licences, licence)
+			}
+		}
+	}
+	return licences
+}
+
+// licenceNames returns the licence names of the first line of a DEP-5 License field, e.g. `GPL-2+ or Artistic`, mapped
+// to SPDX identifiers when known
+func licenceNames(field string) []string {
+	synopsis := strings.TrimSpace(strings.SplitN(field, "\n", 2)[0])
+	if synopsis == "" {
+		return nil
+	}
+	if licence, ok := debianLicences[synopsis]; ok {
+		return []string{licence}
+	}
+	if strings.Contains(strings.ToLower(synopsis), " with ") {
+		// e.g. `GPL-2+ with Autoconf-exception`, kept as a single licence
+		return []string{synopsis}
+	}
+
+	words := strings.Fields(synopsis)
+	var licences []string
+	var operator string
+	for _, word := range words {
+		switch strings.ToLower(word) {
+		case "or", "and":
+			operator = strings.ToUpper(word)
+			continue
+		case "|":
+			operator = "OR"
+			continue
+		case "&":
+			operator = "AND"
+			continue
+		}
+		licence := strings.TrimSuffix(word, ",")
+		if spdx, ok := debianLicences[licence]; ok {
+			licence = spdx
+		}
+		licences = append(licences, licence)
+	}
+	if len(licences) > 1 {
+		if operator == "" {
+			return []string{synopsis}
+		}
+		return []string{"(" + strings.Join(licences, " "+operator+" ") + ")"}
+	}
+	return licences
+}
+
+func apkPackages(fs rootFS) ([]Package, error) {
+	content, ok := fs[apkInstalled]
+	if !ok {
+		return nil, nil
+	}
+
+	var packages []Package
+	for _, entry := range strings.Split(strings.Replace(string(content), "\r\n", "\n", -1), "\n\n") {
+		pkg := Package{Manager: ManagerApk}
+		for _, line := range strings.Split(entry, "\n") {
+			if len(line) < 2 || line[1] != ':' {
+				continue
+			}
+			switch value := strings.TrimSpace(line[2:]); line[0] {
+			case 'P':
+				pkg.Name = value
+			case 'V':
+				pkg.Version = value
+			case 'L':
+				pkg.Licences = apkLicences(value)
+			}
+		}
+		if pkg.Name == "" {
+			if strings.TrimSpace(entry) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("%s: package without name", apkInstalled)
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// apkLicences returns the licence of an apk package, which is an SPDX expression for recent packages, or a list of
+// licences separated by spaces for older ones
+func apkLicences(value string) []string {
+	fields := strings.Fields(value)
+	for _, field := range fields {
+		switch field {
+		case "AND", "OR", "WITH":
+			return []string{value}
+		}
+	}
+	return fields
+}
+
+// stanza is a paragraph of a debian control file, e.g. the dpkg status database or a copyright file
+type stanza map[string]string
+
+func (s stanza) value(field string) string {
+	return strings.TrimSpace(s[strings.ToLower(field)])
+}
+
+// readStanzas reads the paragraphs of a debian control file. Field names are case-insensitive, and continuation lines
+// are appended to the value of their field.
+func readStanzas(content string) []stanza {
+	var stanzas []stanza
+	current := make(stanza)
+	var lastField string
+	for _, line := range strings.Split(strings.Replace(content, "\r\n", "\n", -1), "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			if len(current) > 0 {
+				stanzas = append(stanzas, current)
+				current = make(stanza)
+			}
+			lastField = ""
+		case strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"):
+			if lastField != "" {
+				current[lastField] += "\n" + strings.TrimSpace(line)
+			}
+		default:
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			lastField = strings.ToLower(strings.TrimSpace(parts[0]))
+			current[lastField] = strings.TrimSpace(parts[1])
+		}
+	}
+	if len(current) > 0 {
+		stanzas = append(stanzas, current)
+	}
+	return stanzas
+}
@@ -0,0 +1,129 @@
+package ospackages
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"testing"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestOSPackages(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/ospackages.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "OS Packages Suite", []Reporter{junitReporter})
+}
+
+var _ = Describe("OS packages", func() {
+
+	It("should read the dpkg packages installed in a root filesystem with their copyright licences", func() {
+		// when
+		packages, err := Packages("testdata/debian")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(packages).To(HaveLen(3))
+		Expect(packages[0]).To(Equal(Package{
+			Name:          "base-files",
+			Version:       "12.4+deb12u5",
+			Manager:       ManagerDpkg,
+			Licences:      []string{"GPL-2.0-or-later"},
+			CopyrightFile: "usr/share/doc/base-files/copyright",
+		}))
+		Expect(packages[1].String()).To(Equal("libc6@2.36-9+deb12u4"))
+		Expect(packages[1].Licences).To(Equal([]string{"LGPL-2.1-or-later", "BSD-3-Clause", "(GPL-2.0-or-later OR Artistic-1.0-Perl)"}))
+		Expect(packages[1].DeclaredLicence()).To(Equal("(LGPL-2.1-or-later AND BSD-3-Clause AND (GPL-2.0-or-later OR Artistic-1.0-Perl))"))
+	})
+
+	It("should not find licences in copyright files which are not machine-readable", func() {
+		// when
+		packages, err := Packages("testdata/debian")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(packages[2].Name).To(Equal("tzdata"))
+		Expect(packages[2].CopyrightFile).To(Equal("usr/share/doc/tzdata/copyright"))
+		Expect(packages[2].Licences).To(BeEmpty())
+		Expect(packages[2].DeclaredLicence()).To(BeEmpty())
+	})
+
+	It("should read the apk packages installed in a root filesystem with their licences", func() {
+		// when
+		packages, err := Packages("testdata/alpine")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(packages).To(HaveLen(4))
+		Expect(packages[0]).To(Equal(Package{Name: "busybox", Version: "1.36.1-r15", Manager: ManagerApk, Licences: []string{"GPL-2.0-only"}}))
+		Expect(packages[1].DeclaredLicence()).To(Equal("MPL-2.0 AND MIT"))
+		Expect(packages[2].String()).To(Equal("musl@1.2.4-r2"))
+		Expect(packages[3].DeclaredLicence()).To(Equal("(Zlib AND MIT)"))
+	})
+
+	It("should read the packages of a docker save tarball by applying its layers in order", func() {
+		// when
+		packages, err := Packages("testdata/image.tar")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(packages).To(HaveLen(3))
+		Expect(packages[0].String()).To(Equal("base-files@12.4+deb12u5"))
+		Expect(packages[0].Licences).To(Equal([]string{"GPL-2.0-or-later"}))
+		Expect(packages[1].String()).To(Equal("libfoo1@1.0-1"))
+		Expect(packages[1].CopyrightFile).To(BeEmpty())
+		Expect(packages[1].Licences).To(BeEmpty())
+		Expect(packages[2].String()).To(Equal("zlib1g@1:1.2.13.dfsg-1"))
+		Expect(packages[2].Licences).To(Equal([]string{"Zlib"}))
+	})
+
+	It("should follow the links of a tarball to the copyright files of other packages", func() {
+		// when
+		packages, err := Packages("testdata/symlinks.tar")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(packages).To(HaveLen(4))
+		Expect(packages[0].String()).To(Equal("libbar1@2.0-1"))
+		Expect(packages[0].CopyrightFile).To(Equal("usr/share/doc/libbar1/copyright"))
+		Expect(packages[0].Licences).To(Equal([]string{"MIT"}))
+		Expect(packages[1].String()).To(Equal("libfoo-dev@1.0-1"))
+		Expect(packages[1].CopyrightFile).To(Equal("usr/share/doc/libfoo-dev/copyright"))
+		Expect(packages[1].Licences).To(Equal([]string{"MIT"}))
+		Expect(packages[2].String()).To(Equal("libfoo1@1.0-1"))
+		Expect(packages[2].Licences).To(Equal([]string{"MIT"}))
+		Expect(packages[3].String()).To(Equal("libloop1@3.0-1"))
+		Expect(packages[3].CopyrightFile).To(BeEmpty())
+	})
+
+	It("should fail when the root filesystem has no package database", func() {
+		// when
+		_, err := Packages("testdata/debian/usr")
+
+		// then
+		Expect(err).To(MatchError(ContainSubstring("no dpkg or apk package database found")))
+	})
+
+	It("should keep debian licences with exceptions as a single licence", func() {
+		Expect(licenceNames("GPL-2+ with Autoconf-exception")).To(Equal([]string{"GPL-2+ with Autoconf-exception"}))
+		Expect(licenceNames("Expat\n the licence text")).To(Equal([]string{"MIT"}))
+		Expect(licenceNames("GPL-2 | BSD-2-clause")).To(Equal([]string{"(GPL-2.0-only OR BSD-2-Clause)"}))
+	})
+
+	It("should map the debian licences to SPDX licences, except public-domain which has no SPDX identifier", func() {
+		for name, licence := range debianLicences {
+			if name == "public-domain" {
+				continue
+			}
+			Expect(detection.IsSPDXLicence(licence)).To(BeTrue(), "licence %s of debian licence %s", licence, name)
+		}
+	})
+
+})
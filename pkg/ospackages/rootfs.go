@@ -0,0 +1,309 @@
+package ospackages
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	dpkgStatus    = "var/lib/dpkg/status"
+	dpkgStatusDir = "var/lib/dpkg/status.d/"
+	apkInstalled  = "lib/apk/db/installed"
+	docDir        = "usr/share/doc/"
+	whiteout      = ".wh."
+	opaqueDir     = ".wh..wh..opq"
+	// maxLinks is the number of links followed to resolve a path, beyond which the links are taken for a loop
+	maxLinks = 40
+)
+
+// rootFS holds the package databases and copyright files of a root filesystem, keyed by their path relative to the root
+type rootFS map[string][]byte
+
+// isPackageFile returns true for the files of a root filesystem describing its OS packages
+func isPackageFile(name string) bool {
+	if name == dpkgStatus || name == apkInstalled {
+		return true
+	}
+	if strings.HasPrefix(name, dpkgStatusDir) {
+		return !strings.Contains(strings.TrimPrefix(name, dpkgStatusDir), "/")
+	}
+	if strings.HasPrefix(name, docDir) {
+		parts := strings.Split(strings.TrimPrefix(name, docDir), "/")
+		return len(parts) == 2 && parts[1] == "copyright"
+	}
+	return false
+}
+
+// isPackageLink returns true for the links of a root filesystem which may lead to the copyright files of OS packages,
+// i.e. the doc directories of packages and their copyright files
+func isPackageLink(name string) bool {
+	if !strings.HasPrefix(name, docDir) {
+		return false
+	}
+	parts := strings.Split(strings.TrimPrefix(name, docDir), "/")
+	return len(parts) == 1 || (len(parts) == 2 && parts[1] == "copyright")
+}
+
+// readRootFS reads the package files of an unpacked root filesystem directory, or of a `docker save` or root filesystem
+// tarball
+func readRootFS(rootfsPath string) (rootFS, error) {
+	info, err := os.Stat(rootfsPath)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return readRootFSDir(rootfsPath)
+	}
+	return readImage(rootfsPath)
+}
+
+func readRootFSDir(root string) (rootFS, error) {
+	var names []string
+	for _, pattern := range []string{dpkgStatus, dpkgStatusDir + "*", apkInstalled, docDir + "*/copyright"} {
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, matches...)
+	}
+
+	fs := make(rootFS)
+	for _, name := range names {
+		info, err := os.Stat(name)
+		if err != nil || info.IsDir() {
+			// e.g. dangling symlinks to copyright files of other packages
+			continue
+		}
+		content, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		relPath, err := filepath.Rel(root, name)
+		if err != nil {
+			return nil, err
+		}
+		fs[filepath.ToSlash(relPath)] = content
+	}
+	return fs, nil
+}
+
+// layer is the package files of an image layer, with the paths it deletes from the layers below
+type layer struct {
+	files rootFS
+	// links maps the paths of the symbolic and hard links to package files to the path of their target
+	links      map[string]string
+	whiteouts  []string
+	opaqueDirs []string
+}
+
+// readImage reads the package files of a `docker save` tarball, applying its layers in the order of its manifest.
+// Tarballs without manifest are read as a root filesystem, e.g. from `docker export`.
+func readImage(tarPath string) (rootFS, error) {
+	f, err := os.Open(tarPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	outer, err := readLayer(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read tarball %s: %v", tarPath, err)
+	}
+	manifestContent, ok := outer.manifest()
+	if !ok {
+		return outer.layer.resolveLinks(), nil
+	}
+
+	var manifest []struct {
+		Layers []string `json:"Layers"`
+	}
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return nil, fmt.Errorf("unable to parse manifest.json of %s: %v", tarPath, err)
+	}
+	if len(manifest) != 1 {
+		return nil, fmt.Errorf("%s should hold a single image, found %d", tarPath, len(manifest))
+	}
+
+	image := &layer{files: make(rootFS), links: make(map[string]string)}
+	for _, layerName := range manifest[0].Layers {
+		l, ok := outer.layers[path.Clean(layerName)]
+		if !ok {
+			return nil, fmt.Errorf("layer %s of %s not found", layerName, tarPath)
+		}
+		image.apply(l)
+	}
+	return image.resolveLinks(), nil
+}
+
+// tarball is the content of a tarball: its own package files, and the nested layer tarballs of docker images
+type tarball struct {
+	layer
+	layers map[string]*layer
+	other  map[string][]byte
+}
+
+func (t *tarball) manifest() ([]byte, bool) {
+	content, ok := t.other["manifest.json"]
+	return content, ok
+}
+
+// readLayer reads a tarball, which may be gzip compressed, keeping its package files, its whiteouts, its nested layer
+// tarballs and its manifest.json
+func readLayer(r io.Reader) (*tarball, error) {
+	buffered := bufio.NewReader(r)
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return readTar(gz)
+	}
+	return readTar(buffered)
+}
+
+func readTar(r io.Reader) (*tarball, error) {
+	t := &tarball{layer: layer{files: make(rootFS), links: make(map[string]string)}, layers: make(map[string]*layer), other: make(map[string][]byte)}
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return t, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		base := path.Base(name)
+		switch {
+		case base == opaqueDir:
+			t.opaqueDirs = append(t.opaqueDirs, path.Dir(name))
+		case strings.HasPrefix(base, whiteout):
+			t.whiteouts = append(t.whiteouts, path.Join(path.Dir(name), strings.TrimPrefix(base, whiteout)))
+		case header.Typeflag == tar.TypeSymlink && isPackageLink(name):
+			target := header.Linkname
+			if !path.IsAbs(target) {
+				target = path.Join(path.Dir(name), target)
+			}
+			t.links[name] = strings.TrimPrefix(path.Clean("/"+target), "/")
+		case header.Typeflag == tar.TypeLink && isPackageLink(name):
+			// hard links are relative to the root of the tarball
+			t.links[name] = strings.TrimPrefix(path.Clean("/"+header.Linkname), "/")
+		case header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA:
+		case isPackageFile(name):
+			if t.files[name], err = ioutil.ReadAll(reader); err != nil {
+				return nil, err
+			}
+		case name == "manifest.json":
+			if t.other[name], err = ioutil.ReadAll(reader); err != nil {
+				return nil, err
+			}
+		case strings.HasSuffix(name, ".tar") || strings.HasPrefix(name, "blobs/"):
+			// layers of docker save tarballs, or blobs of OCI layouts, which are not all layers
+			if nested, err := readLayer(reader); err == nil {
+				t.layers[name] = &nested.layer
+			}
+		}
+	}
+}
+
+// apply applies a layer over the layers below, removing the files and links it deletes or replaces before adding its
+// own files and links
+func (l *layer) apply(upper *layer) {
+	for _, dir := range upper.opaqueDirs {
+		l.remove(dir, true)
+	}
+	for _, deleted := range upper.whiteouts {
+		l.remove(deleted, false)
+	}
+	for name, content := range upper.files {
+		delete(l.links, name)
+		l.files[name] = content
+	}
+	for name, target := range upper.links {
+		l.remove(name, false)
+		l.links[name] = target
+	}
+}
+
+func (l *layer) remove(name string, childrenOnly bool) {
+	removed := func(existing string) bool {
+		return (!childrenOnly && existing == name) || strings.HasPrefix(existing, name+"/")
+	}
+	for existing := range l.files {
+		if removed(existing) {
+			delete(l.files, existing)
+		}
+	}
+	for existing := range l.links {
+		if removed(existing) {
+			delete(l.links, existing)
+		}
+	}
+}
+
+// resolveLinks returns the package files of the layer, along with the package files its links lead to, under the path
+// of the links, e.g. the copyright file of a package whose doc directory is a link to the doc directory of another
+// package. Links leading outside of the package files are skipped.
+func (l *layer) resolveLinks() rootFS {
+	fs := make(rootFS)
+	for name, content := range l.files {
+		fs[name] = content
+	}
+	for link := range l.links {
+		target, ok := l.resolve(link)
+		if !ok {
+			continue
+		}
+		if content, ok := l.files[target]; ok && isPackageFile(link) {
+			fs[link] = content
+		}
+		for name, content := range l.files {
+			if linked := link + strings.TrimPrefix(name, target); strings.HasPrefix(name, target+"/") && isPackageFile(linked) {
+				fs[linked] = content
+			}
+		}
+	}
+	return fs
+}
+
+// resolve returns the path of the package file a path leads to, following the links of the path and of its parent
+// directories, or false when the links loop
+func (l *layer) resolve(name string) (string, bool) {
+	for i := 0; i < maxLinks; i++ {
+		linked := false
+		for dir := name; dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if target, ok := l.links[dir]; ok {
+				name = target + strings.TrimPrefix(name, dir)
+				linked = true
+				break
+			}
+		}
+		if !linked {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// names returns the sorted paths of the files of the root filesystem matching the given prefix
+func (fs rootFS) names(prefix string) []string {
+	var names []string
+	for name := range fs {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
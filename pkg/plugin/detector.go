@@ -0,0 +1,46 @@
+package plugin
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+)
+
+// detector is an implementation of LicenceDetector that runs a detector plugin
+type detector struct {
+	config Config
+}
+
+// NewDetector creates a LicenceDetector running the given detector plugin
+func NewDetector(config Config) detection.LicenceDetector {
+	return &detector{config: config}
+}
+
+// Detect runs the plugin for all the paths at once. When the plugin fails, its failure is reported as the error of each
+// path rather than failing the whole detection.
+func (d *detector) Detect(paths []string) ([]detection.Result, error) {
+	response, err := run(d.config, paths)
+	if err != nil {
+		log.Warnf("Licence detection failed: %v", err)
+		var results []detection.Result
+		for _, path := range paths {
+			results = append(results, detection.Result{Project: path, ErrStr: err.Error()})
+		}
+		return results, nil
+	}
+
+	resultsByPath := make(map[string]detection.Result)
+	for _, result := range response.Results {
+		resultsByPath[result.Project] = result
+	}
+
+	var results []detection.Result
+	for _, path := range paths {
+		result, ok := resultsByPath[path]
+		if !ok {
+			result = detection.Result{Project: path, ErrStr: fmt.Sprintf("plugin %s returned no result", d.config.Name)}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
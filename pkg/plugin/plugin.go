@@ -0,0 +1,159 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProtocolVersion is the version of the plugin protocol, which plugins must echo in their responses
+const ProtocolVersion = 1
+
+// DefaultTimeout is how long a plugin may run when its configuration has no timeout
+const DefaultTimeout = time.Minute
+
+// Kinds of plugins, i.e. what they are requested to do
+const (
+	// KindDetector plugins detect the licences of the project paths of their request
+	KindDetector = "detector"
+	// KindResolver plugins resolve the projects of the paths of their request, e.g. the dependencies of a project
+	// directory
+	KindResolver = "resolver"
+)
+
+// Request is the JSON document written to the standard input of plugins
+type Request struct {
+	ProtocolVersion int `json:"protocolVersion"`
+	// Kind is the kind of the plugin, i.e. detector or resolver
+	Kind string `json:"kind"`
+	// Paths are the project paths whose licences are detected, or the paths whose projects are resolved
+	Paths []string `json:"paths"`
+}
+
+// Response is the JSON document plugins write to their standard output
+type Response struct {
+	ProtocolVersion int `json:"protocolVersion"`
+	// Results are the detection results of detectors, with their project set to the requested path, or the projects
+	// of resolvers
+	Results []detection.Result `json:"results"`
+	// Error fails the whole request, e.g. when the plugin is misconfigured
+	Error string `json:"error,omitempty"`
+}
+
+// Config is the configuration of a plugin
+type Config struct {
+	Name string `json:"name"`
+	// Kind is the kind of the plugin, i.e. detector or resolver
+	Kind string `json:"kind"`
+	// Command is the executable of the plugin followed by its arguments
+	Command []string `json:"command"`
+	// Timeout is how long the plugin may run for a request, e.g. 30s, DefaultTimeout when empty
+	Timeout Duration `json:"timeout,omitempty"`
+	// Paths are the paths whose projects resolver plugins resolve, the current directory when empty
+	Paths []string `json:"paths,omitempty"`
+}
+
+// Validate returns an error when the configuration is incomplete
+func (c *Config) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("plugin without name")
+	}
+	if c.Kind != KindDetector && c.Kind != KindResolver {
+		return fmt.Errorf("plugin %s has an invalid kind %q, should be one of: %s, %s", c.Name, c.Kind, KindDetector, KindResolver)
+	}
+	if len(c.Command) == 0 {
+		return fmt.Errorf("plugin %s has no command", c.Name)
+	}
+	return nil
+}
+
+// Duration is a time.Duration read from JSON strings, e.g. 30s
+type Duration time.Duration
+
+// UnmarshalJSON parses the duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration should be a string, e.g. 30s: %v", err)
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// run sends the request to the plugin and returns its response, failing when the plugin does not complete in time,
+// exits with an error, or does not answer with a valid response of the same protocol version. Plugins run in their own
+// process group, which is killed on timeout along with the processes they fork.
+func run(config Config, paths []string) (*Response, error) {
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	request, err := json.Marshal(Request{ProtocolVersion: ProtocolVersion, Kind: config.Kind, Paths: paths})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(config.Command[0], config.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := startProcessGroup(cmd); err != nil {
+		return nil, fmt.Errorf("plugin %s failed: %v", config.Name, err)
+	}
+	timedOut, err := waitWithTimeout(cmd, timeout, killProcessGroup)
+	if timedOut {
+		return nil, fmt.Errorf("plugin %s timed out after %s", config.Name, timeout)
+	}
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("plugin %s failed: %v: %s", config.Name, err, message)
+		}
+		return nil, fmt.Errorf("plugin %s failed: %v", config.Name, err)
+	}
+
+	var response Response
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("plugin %s returned an invalid response: %v", config.Name, err)
+	}
+	if response.ProtocolVersion != ProtocolVersion {
+		return nil, fmt.Errorf("plugin %s uses protocol version %d, expected %d", config.Name, response.ProtocolVersion, ProtocolVersion)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("plugin %s failed: %s", config.Name, response.Error)
+	}
+	return &response, nil
+}
+
+// waitWithTimeout waits for the started command, killing it once the timeout has elapsed. The command has only timed out
+// when it was killed and failed as a result, rather than when it completed at the deadline, just before being killed.
+// Commands which have exited are never killed, as their process group may be reused.
+func waitWithTimeout(cmd *exec.Cmd, timeout time.Duration, kill func(*exec.Cmd)) (bool, error) {
+	var mu sync.Mutex
+	var exited, killed bool
+	timer := time.AfterFunc(timeout, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if !exited {
+			kill(cmd)
+			killed = true
+		}
+	})
+	err := cmd.Wait()
+	timer.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	exited = true
+	return killed && err != nil, err
+}
@@ -0,0 +1,157 @@
+package plugin
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestPlugin(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/plugin.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Plugin Suite", []Reporter{junitReporter})
+}
+
+var _ = Describe("plugins", func() {
+
+	Context("detector plugins", func() {
+		It("should return the results of the plugin in the order of the paths", func() {
+			// given
+			d := NewDetector(Config{Name: "scanner", Kind: KindDetector, Command: []string{"testdata/detector.sh"}})
+
+			// when
+			results, err := d.Detect([]string{"c", "b", "a"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(Equal([]detection.Result{
+				{Project: "c", ErrStr: "plugin scanner returned no result"},
+				{Project: "b", ErrStr: "no licence found"},
+				{Project: "a", Matches: []detection.LicenceMatch{{Licence: "LicenseRef-Proprietary", Confidence: 1}}},
+			}))
+		})
+
+		It("should report the failure of the plugin for each path", func() {
+			// given
+			d := NewDetector(Config{Name: "scanner", Kind: KindDetector, Command: []string{"testdata/failing.sh"}})
+
+			// when
+			results, err := d.Detect([]string{"a", "b"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+			Expect(results[0].ErrStr).To(Equal("plugin scanner failed: exit status 3: scanner crashed"))
+			Expect(results[1].ErrStr).To(Equal("plugin scanner failed: exit status 3: scanner crashed"))
+		})
+
+		It("should stop plugins which do not complete in time", func() {
+			// given
+			d := NewDetector(Config{Name: "slow", Kind: KindDetector, Command: []string{"testdata/slow.sh"}, Timeout: Duration(100 * time.Millisecond)})
+
+			// when
+			start := time.Now()
+			results, err := d.Detect([]string{"a"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+			Expect(results[0].ErrStr).To(Equal("plugin slow timed out after 100ms"))
+		})
+
+		It("should not report plugins which complete at the deadline as timed out", func() {
+			// given
+			cmd := exec.Command("sh", "-c", "sleep 0.2")
+			Expect(cmd.Start()).To(Succeed())
+			var kills int
+
+			// when
+			timedOut, err := waitWithTimeout(cmd, 10*time.Millisecond, func(*exec.Cmd) { kills++ })
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kills).To(Equal(1))
+			Expect(timedOut).To(BeFalse())
+		})
+
+		It("should reject responses of another protocol version", func() {
+			// given
+			d := NewDetector(Config{Name: "old", Kind: KindDetector, Command: []string{"testdata/old-protocol.sh"}})
+
+			// when
+			results, err := d.Detect([]string{"a"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].ErrStr).To(Equal("plugin old uses protocol version 0, expected 1"))
+		})
+
+		It("should report the error of the response", func() {
+			// given
+			d := NewDetector(Config{Name: "scanner", Kind: KindDetector, Command: []string{"testdata/error.sh"}})
+
+			// when
+			results, err := d.Detect([]string{"a"})
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].ErrStr).To(Equal("plugin scanner failed: missing scanner licence key"))
+		})
+	})
+
+	Context("resolver plugins", func() {
+		It("should return the projects of the plugin", func() {
+			// given
+			r := &Resolver{Config: Config{Name: "bazel", Kind: KindResolver, Command: []string{"testdata/resolver.sh"}}}
+
+			// when
+			projects, err := r.Resolve()
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(projects).To(Equal([]detection.Result{{
+				Project:         "in-house/lib@1.0.0",
+				Ecosystem:       "in-house",
+				Module:          "in-house/lib",
+				Version:         "1.0.0",
+				Directory:       "libs/lib",
+				DeclaredLicence: "MIT",
+				Scope:           "prod",
+			}}))
+		})
+
+		It("should report the failure of the plugin as an unresolved project", func() {
+			// given
+			r := &Resolver{Config: Config{Name: "bazel", Kind: KindResolver, Command: []string{"testdata/missing.sh"}}}
+
+			// when
+			projects, err := r.Resolve()
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(projects).To(HaveLen(1))
+			Expect(projects[0].Project).To(Equal("bazel"))
+			Expect(projects[0].ErrStr).To(ContainSubstring("plugin bazel failed"))
+		})
+	})
+
+	It("should only accept configurations with a name, a kind and a command", func() {
+		Expect((&Config{Name: "scanner", Kind: KindDetector, Command: []string{"scanner"}}).Validate()).To(Succeed())
+		Expect((&Config{Kind: KindDetector, Command: []string{"scanner"}}).Validate()).ToNot(Succeed())
+		Expect((&Config{Name: "scanner", Kind: "scanner", Command: []string{"scanner"}}).Validate()).ToNot(Succeed())
+		Expect((&Config{Name: "scanner", Kind: KindResolver}).Validate()).ToNot(Succeed())
+	})
+
+})
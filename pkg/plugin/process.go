@@ -0,0 +1,20 @@
+//go:build !windows
+// +build !windows
+
+package plugin
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// startProcessGroup starts the command in its own process group, so that the processes it forks are killed along with it
+func startProcessGroup(cmd *exec.Cmd) error {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd.Start()
+}
+
+// killProcessGroup kills the process group of a command started by startProcessGroup
+func killProcessGroup(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,13 @@
+package plugin
+
+import "os/exec"
+
+// startProcessGroup starts the command. Windows has no process groups, so the processes it forks outlive it.
+func startProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Start()
+}
+
+// killProcessGroup kills the process of the command
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
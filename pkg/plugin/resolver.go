@@ -0,0 +1,32 @@
+package plugin
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+)
+
+// Resolver is an implementation of resolver.Resolver that runs a resolver plugin
+type Resolver struct {
+	Config Config
+}
+
+// Name describes the projects of the resolver
+func (r *Resolver) Name() string {
+	return "projects of plugin " + r.Config.Name
+}
+
+// Resolve runs the plugin for its configured paths. When the plugin fails, its failure is reported as an unresolved
+// project named after the plugin rather than failing the whole resolution.
+func (r *Resolver) Resolve() ([]detection.Result, error) {
+	paths := r.Config.Paths
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	response, err := run(r.Config, paths)
+	if err != nil {
+		log.Warnf("Project resolution failed: %v", err)
+		return []detection.Result{{Project: r.Config.Name, ErrStr: err.Error()}}, nil
+	}
+	return response.Results, nil
+}
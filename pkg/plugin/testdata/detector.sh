@@ -0,0 +1,7 @@
+#!/bin/sh
+# detects a licence for the path a and none for the path b, whatever the request
+cat > /dev/null
+echo '{"protocolVersion": 1, "results": [
+  {"project": "a", "matches": [{"license": "LicenseRef-Proprietary", "confidence": 1}]},
+  {"project": "b", "error": "no licence found"}
+]}'
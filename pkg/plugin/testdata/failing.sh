@@ -0,0 +1,3 @@
+#!/bin/sh
+echo "scanner crashed" >&2
+exit 3
@@ -0,0 +1,4 @@
+#!/bin/sh
+# the plugin forks a child which outlives it unless its whole process group is killed
+cat >/dev/null
+sleep 5
@@ -0,0 +1,150 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/sky-uk/licence-compliance-checker/pkg/compliance"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"github.com/sky-uk/licence-compliance-checker/pkg/plugin"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Policy holds the configuration of a licence compliance check which is kept in a JSON file rather than given as
+// command line options
+type Policy struct {
+	// Plugins are the external detectors and resolvers, run in order
+	Plugins []plugin.Config `json:"plugins"`
+	// Detection configures how the licences of projects are detected, when the default detection is not suitable
+	Detection *Detection `json:"detection,omitempty"`
+	// MismatchSeverity is the severity of the projects whose declared licence differs from their detected licence, i.e.
+	// error or warning
+	MismatchSeverity string `json:"mismatchSeverity,omitempty"`
+	// DirectSeverity and IndirectSeverity are the severities of the violations of direct and indirect dependencies, i.e.
+	// error or warning, unless given as command line options
+	DirectSeverity   string `json:"directSeverity,omitempty"`
+	IndirectSeverity string `json:"indirectSeverity,omitempty"`
+	// LicenceTemplates is the directory of the texts of the licences which are not in the SPDX licence list, named
+	// after their LicenseRef- identifier
+	LicenceTemplates string `json:"licenceTemplates,omitempty"`
+}
+
+// Detection configures the chain of detectors identifying the licences of projects. By default, the licence files of
+// projects are detected first, falling back to the detector plugins in order.
+type Detection struct {
+	// Strategy is how the results of the detectors are combined, i.e. first-hit, fallback or consensus
+	Strategy string `json:"strategy"`
+	// Detectors are the names of the detectors to run, in order: files, spdx-headers, declared or the name of a detector
+	// plugin
+	Detectors []string `json:"detectors"`
+}
+
+// Load reads the policy of the given JSON file. Plugin commands given as relative paths, e.g. ./scanner, are relative
+// to the directory of the policy file, while bare command names are looked up in PATH. The licence templates directory
+// is also relative to the directory of the policy file.
+func Load(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var policy Policy
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("unable to parse policy %s: %v", path, err)
+	}
+
+	for i := range policy.Plugins {
+		config := &policy.Plugins[i]
+		if err := config.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid policy %s: %v", path, err)
+		}
+		if command := config.Command[0]; !filepath.IsAbs(command) && strings.ContainsRune(command, '/') {
+			if config.Command[0], err = filepath.Abs(filepath.Join(filepath.Dir(path), filepath.FromSlash(command))); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := policy.validateDetection(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %v", path, err)
+	}
+	if policy.LicenceTemplates != "" && !filepath.IsAbs(policy.LicenceTemplates) {
+		policy.LicenceTemplates = filepath.Join(filepath.Dir(path), policy.LicenceTemplates)
+	}
+	for _, severity := range []string{policy.MismatchSeverity, policy.DirectSeverity, policy.IndirectSeverity} {
+		if severity == "" {
+			continue
+		}
+		if _, err := compliance.ParseSeverity(severity); err != nil {
+			return nil, fmt.Errorf("invalid policy %s: %v", path, err)
+		}
+	}
+	return &policy, nil
+}
+
+func (p *Policy) validateDetection() error {
+	if p.Detection == nil {
+		return nil
+	}
+	if _, err := detection.ParseStrategy(p.Detection.Strategy); err != nil {
+		return err
+	}
+	if len(p.Detection.Detectors) == 0 {
+		return fmt.Errorf("detection has no detectors")
+	}
+	detectors := map[string]bool{detection.DetectorFiles: true, detection.DetectorDeclared: true, detection.DetectorSPDXHeaders: true}
+	for _, config := range p.Detectors() {
+		detectors[config.Name] = true
+	}
+	for _, name := range p.Detection.Detectors {
+		if !detectors[name] {
+			return fmt.Errorf("detection uses unknown detector %q", name)
+		}
+	}
+	return nil
+}
+
+// DetectionStrategy returns the strategy combining the results of the detectors, fallback by default
+func (p *Policy) DetectionStrategy() detection.Strategy {
+	if p.Detection == nil {
+		return detection.StrategyFallback
+	}
+	strategy, _ := detection.ParseStrategy(p.Detection.Strategy)
+	return strategy
+}
+
+// DetectorNames returns the names of the detectors to run, in order. By default, these are the licence files detector
+// followed by the detector plugins.
+func (p *Policy) DetectorNames() []string {
+	if p.Detection != nil {
+		return p.Detection.Detectors
+	}
+	names := []string{detection.DetectorFiles}
+	for _, config := range p.Detectors() {
+		names = append(names, config.Name)
+	}
+	return names
+}
+
+// Detectors returns the configuration of the detector plugins
+func (p *Policy) Detectors() []plugin.Config {
+	return p.plugins(plugin.KindDetector)
+}
+
+// Resolvers returns the configuration of the resolver plugins
+func (p *Policy) Resolvers() []plugin.Config {
+	return p.plugins(plugin.KindResolver)
+}
+
+func (p *Policy) plugins(kind string) []plugin.Config {
+	var plugins []plugin.Config
+	for _, config := range p.Plugins {
+		if config.Kind == kind {
+			plugins = append(plugins, config)
+		}
+	}
+	return plugins
+}
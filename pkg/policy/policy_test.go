@@ -0,0 +1,120 @@
+package policy
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"github.com/sky-uk/licence-compliance-checker/pkg/plugin"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestPolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/policy.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Policy Suite", []Reporter{junitReporter})
+}
+
+var _ = Describe("policy", func() {
+
+	It("should load the plugins of the policy", func() {
+		// when
+		p, err := Load("testdata/policy.json")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		scanner, err := filepath.Abs("testdata/scanner.sh")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.Detectors()).To(Equal([]plugin.Config{
+			{Name: "scanner", Kind: plugin.KindDetector, Command: []string{scanner, "--json"}, Timeout: plugin.Duration(30 * time.Second)},
+		}))
+		Expect(p.Resolvers()).To(Equal([]plugin.Config{
+			{Name: "bazel", Kind: plugin.KindResolver, Command: []string{"bazel-deps"}, Paths: []string{"services/api"}},
+		}))
+	})
+
+	It("should read the licence templates relative to the policy", func() {
+		// when
+		p, err := Load("testdata/policy.json")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.LicenceTemplates).To(Equal(filepath.Join("testdata", "licences")))
+	})
+
+	It("should detect licence files then run the detector plugins by default", func() {
+		// when
+		p, err := Load("testdata/policy.json")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.DetectionStrategy()).To(Equal(detection.StrategyFallback))
+		Expect(p.DetectorNames()).To(Equal([]string{"files", "scanner"}))
+	})
+
+	It("should load the detection of the policy", func() {
+		// when
+		p, err := Load("testdata/consensus.json")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.DetectionStrategy()).To(Equal(detection.StrategyConsensus))
+		Expect(p.DetectorNames()).To(Equal([]string{"scanner", "files", "declared"}))
+		Expect(p.MismatchSeverity).To(Equal("error"))
+	})
+
+	It("should reject detections with unknown detectors", func() {
+		// when
+		_, err := Load("testdata/unknown-detector.json")
+
+		// then
+		Expect(err).To(MatchError(ContainSubstring(`detection uses unknown detector "licensee"`)))
+	})
+
+	It("should reject invalid plugins", func() {
+		// when
+		_, err := Load("testdata/invalid-kind.json")
+
+		// then
+		Expect(err).To(MatchError(ContainSubstring(`plugin scanner has an invalid kind "scanner"`)))
+	})
+
+	It("should load the severities of the policy", func() {
+		// when
+		p, err := Load("testdata/severities.json")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.DirectSeverity).To(Equal("error"))
+		Expect(p.IndirectSeverity).To(Equal("warning"))
+	})
+
+	It("should reject invalid severities", func() {
+		// when
+		_, mismatchErr := Load("testdata/invalid-severity.json")
+		_, indirectErr := Load("testdata/invalid-indirect-severity.json")
+
+		// then
+		Expect(mismatchErr).To(MatchError(ContainSubstring(`invalid severity "fatal"`)))
+		Expect(indirectErr).To(MatchError(ContainSubstring(`invalid severity "info"`)))
+	})
+
+	It("should reject unknown fields", func() {
+		// when
+		_, err := Load("testdata/unknown-field.json")
+
+		// then
+		Expect(err).To(MatchError(ContainSubstring(`unknown field "plugin"`)))
+	})
+
+})
@@ -0,0 +1,254 @@
+package python
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// classifierLicences maps the licence trove classifiers which name a single licence to their SPDX identifier.
+// Classifiers naming a family of licences, e.g. `License :: OSI Approved :: BSD License`, are left out.
+var classifierLicences = map[string]string{
+	"License :: OSI Approved :: MIT License":                                             "MIT",
+	"License :: OSI Approved :: MIT No Attribution License (MIT-0)":                      "MIT-0",
+	"License :: OSI Approved :: ISC License (ISCL)":                                      "ISC",
+	"License :: OSI Approved :: Python Software Foundation License":                      "PSF-2.0",
+	"License :: OSI Approved :: Mozilla Public License 2.0 (MPL 2.0)":                    "MPL-2.0",
+	"License :: OSI Approved :: GNU General Public License v2 (GPLv2)":                   "GPL-2.0-only",
+	"License :: OSI Approved :: GNU General Public License v2 or later (GPLv2+)":         "GPL-2.0-or-later",
+	"License :: OSI Approved :: GNU General Public License v3 (GPLv3)":                   "GPL-3.0-only",
+	"License :: OSI Approved :: GNU General Public License v3 or later (GPLv3+)":         "GPL-3.0-or-later",
+	"License :: OSI Approved :: GNU Affero General Public License v3":                    "AGPL-3.0-only",
+	"License :: OSI Approved :: GNU Affero General Public License v3 or later (AGPLv3+)": "AGPL-3.0-or-later",
+	"License :: OSI Approved :: GNU Lesser General Public License v2 (LGPLv2)":           "LGPL-2.0-only",
+	"License :: OSI Approved :: GNU Lesser General Public License v2 or later (LGPLv2+)": "LGPL-2.0-or-later",
+	"License :: OSI Approved :: GNU Lesser General Public License v3 (LGPLv3)":           "LGPL-3.0-only",
+	"License :: OSI Approved :: GNU Lesser General Public License v3 or later (LGPLv3+)": "LGPL-3.0-or-later",
+	"License :: OSI Approved :: The Unlicense (Unlicense)":                               "Unlicense",
+	"License :: OSI Approved :: Zope Public License":                                     "ZPL-2.1",
+	"License :: CC0 1.0 Universal (CC0 1.0) Public Domain Dedication":                    "CC0-1.0",
+}
+
+// Distribution is the description of a python distribution installed into a site-packages directory
+type Distribution struct {
+	Name    string
+	Version string
+	// MetadataDir is the `.dist-info` or `.egg-info` directory of the distribution
+	MetadataDir string
+	// LicenceDir is the directory containing the licence files of the distribution, within its metadata directory
+	LicenceDir string
+	// License is the value of the METADATA `License` field, when it names a licence rather than containing its text
+	License string
+	// LicenseExpression is the value of the METADATA `License-Expression` field, an SPDX expression
+	LicenseExpression string
+	// Classifiers are the licence trove classifiers of the distribution, e.g. `License :: OSI Approved :: MIT License`
+	Classifiers []string
+	// LicenseFiles are the paths of the licence files relative to the metadata directory
+	LicenseFiles []string
+	// Err is the error reading the metadata of the distribution, whose name and version are then taken from the name
+	// of its metadata directory
+	Err error
+}
+
+// String returns the identity of the distribution, i.e. its name and version
+func (d *Distribution) String() string {
+	if d.Version == "" {
+		return d.Name
+	}
+	return d.Name + "@" + d.Version
+}
+
+// DeclaredLicence returns the licence declared by the distribution metadata, preferring the `License-Expression`
+// field, then the licence classifiers which map to an SPDX identifier, then the `License` field when it is an SPDX
+// identifier, as it is free text, e.g. `GPLv3` or `BSD`
+func (d *Distribution) DeclaredLicence() string {
+	if d.LicenseExpression != "" {
+		return d.LicenseExpression
+	}
+
+	var licences []string
+	for _, classifier := range d.Classifiers {
+		if licence, ok := classifierLicences[classifier]; ok {
+			licences = append(licences, licence)
+		}
+	}
+	if len(licences) > 1 {
+		return "(" + strings.Join(licences, " OR ") + ")"
+	}
+	if len(licences) == 1 {
+		return licences[0]
+	}
+
+	if detection.IsSPDXLicence(d.License) {
+		return d.License
+	}
+	return ""
+}
+
+// SitePackages returns the site-packages directories of the given directory, i.e. the directory itself when it contains
+// python distributions, or the site-packages directories of a virtualenv
+func SitePackages(dir string) ([]string, error) {
+	if info, err := os.Stat(dir); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	var sitePackages []string
+	for _, pattern := range []string{"lib/python*/site-packages", "lib64/python*/site-packages", "Lib/site-packages"} {
+		matches, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, err
+		}
+		sitePackages = append(sitePackages, matches...)
+	}
+	if len(sitePackages) == 0 {
+		sitePackages = append(sitePackages, dir)
+	}
+	return sitePackages, nil
+}
+
+// Distributions returns the python distributions installed into the given site-packages directories, sorted by name.
+// Distributions whose metadata cannot be read are returned with their Err.
+func Distributions(sitePackagesDirs []string) ([]Distribution, error) {
+	var distributions []Distribution
+	found := make(map[string]bool)
+	for _, sitePackages := range sitePackagesDirs {
+		for _, pattern := range []string{"*.dist-info", "*.egg-info"} {
+			metadataDirs, err := filepath.Glob(filepath.Join(sitePackages, pattern))
+			if err != nil {
+				return nil, err
+			}
+			for _, metadataDir := range metadataDirs {
+				if info, err := os.Stat(metadataDir); err != nil || !info.IsDir() {
+					continue
+				}
+				distribution, err := readDistribution(metadataDir)
+				if err != nil {
+					distribution = unreadableDistribution(metadataDir, err)
+				}
+				// lib and lib64 are often the same directory
+				if found[distribution.String()] {
+					continue
+				}
+				found[distribution.String()] = true
+				distributions = append(distributions, *distribution)
+			}
+		}
+	}
+
+	sort.Slice(distributions, func(i, j int) bool {
+		return strings.ToLower(distributions[i].Name) < strings.ToLower(distributions[j].Name)
+	})
+	return distributions, nil
+}
+
+func readDistribution(metadataDir string) (*Distribution, error) {
+	metadataFile := filepath.Join(metadataDir, "METADATA")
+	if strings.HasSuffix(metadataDir, ".egg-info") {
+		metadataFile = filepath.Join(metadataDir, "PKG-INFO")
+	}
+
+	fields, err := readMetadata(metadataFile)
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := &Distribution{
+		MetadataDir:       metadataDir,
+		LicenceDir:        metadataDir,
+		LicenseExpression: first(fields["License-Expression"]),
+		Classifiers:       licenceClassifiers(fields["Classifier"]),
+		LicenseFiles:      fields["License-File"],
+	}
+	if distribution.Name = first(fields["Name"]); distribution.Name == "" {
+		return nil, fmt.Errorf("%s: missing distribution Name", metadataFile)
+	}
+	distribution.Version = first(fields["Version"])
+
+	// the License field sometimes holds the whole licence text rather than its name
+	if license := first(fields["License"]); license != "" && !strings.EqualFold(license, "UNKNOWN") && !strings.Contains(license, "\n") && len(license) <= 100 {
+		distribution.License = license
+	}
+
+	// licence files are either in the metadata directory, or from metadata 2.4 in its licenses subdirectory
+	for _, licenseFile := range distribution.LicenseFiles {
+		for _, dir := range []string{filepath.Join(metadataDir, "licenses"), metadataDir} {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(licenseFile))); err == nil {
+				distribution.LicenceDir = filepath.Dir(filepath.Join(dir, filepath.FromSlash(licenseFile)))
+				return distribution, nil
+			}
+		}
+	}
+	if info, err := os.Stat(filepath.Join(metadataDir, "licenses")); err == nil && info.IsDir() {
+		distribution.LicenceDir = filepath.Join(metadataDir, "licenses")
+	}
+	return distribution, nil
+}
+
+// unreadableDistribution returns a distribution whose metadata cannot be read, named after its metadata directory, e.g.
+// `name-1.0.dist-info` or `name-1.0-py3.11.egg-info`
+func unreadableDistribution(metadataDir string, err error) *Distribution {
+	base := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(metadataDir), ".dist-info"), ".egg-info")
+	parts := strings.SplitN(base, "-", 3)
+	distribution := &Distribution{Name: parts[0], MetadataDir: metadataDir, LicenceDir: metadataDir, Err: err}
+	if len(parts) > 1 {
+		distribution.Version = parts[1]
+	}
+	return distribution
+}
+
+// readMetadata reads the header fields of a METADATA or PKG-INFO file, which use the email header format.
+// Continuation lines are appended to the value of their field, and the message body, i.e. the description, is skipped.
+func readMetadata(path string) (map[string][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fields := make(map[string][]string)
+	var lastField string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && lastField != "" {
+			values := fields[lastField]
+			values[len(values)-1] += "\n" + strings.TrimSpace(line)
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s: invalid metadata line %q", path, line)
+		}
+		lastField = strings.TrimSpace(parts[0])
+		fields[lastField] = append(fields[lastField], strings.TrimSpace(parts[1]))
+	}
+	return fields, scanner.Err()
+}
+
+func licenceClassifiers(classifiers []string) []string {
+	var licenceClassifiers []string
+	for _, classifier := range classifiers {
+		if strings.HasPrefix(classifier, "License ::") {
+			licenceClassifiers = append(licenceClassifiers, classifier)
+		}
+	}
+	return licenceClassifiers
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
@@ -0,0 +1,128 @@
+package python
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"path/filepath"
+	"testing"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestPython(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/python.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Python Suite", []Reporter{junitReporter})
+}
+
+var sitePackages = filepath.Join("testdata", "venv", "lib", "python3.11", "site-packages")
+
+var _ = Describe("python distributions", func() {
+
+	It("should find the site-packages directories of a virtualenv", func() {
+		Expect(SitePackages("testdata/venv")).To(Equal([]string{sitePackages}))
+		Expect(SitePackages(sitePackages)).To(Equal([]string{sitePackages}))
+	})
+
+	It("should fail when the directory does not exist", func() {
+		_, err := SitePackages("testdata/does-not-exist")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should list the installed distributions with their licence metadata", func() {
+		// when
+		distributions, err := Distributions([]string{sitePackages})
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(distributions).To(Equal([]Distribution{
+			{
+				Name:              "attrs",
+				Version:           "23.1.0",
+				MetadataDir:       filepath.Join(sitePackages, "attrs-23.1.0.dist-info"),
+				LicenceDir:        filepath.Join(sitePackages, "attrs-23.1.0.dist-info", "licenses"),
+				LicenseExpression: "MIT",
+				Classifiers:       []string{"License :: OSI Approved :: MIT License"},
+				LicenseFiles:      []string{"LICENSE"},
+			},
+			{
+				Name:        "Legacy",
+				Version:     "1.0",
+				MetadataDir: filepath.Join(sitePackages, "legacy-1.0-py3.11.egg-info"),
+				LicenceDir:  filepath.Join(sitePackages, "legacy-1.0-py3.11.egg-info"),
+				Classifiers: []string{"License :: OSI Approved :: BSD License"},
+			},
+			{
+				Name:         "requests",
+				Version:      "2.31.0",
+				MetadataDir:  filepath.Join(sitePackages, "requests-2.31.0.dist-info"),
+				LicenceDir:   filepath.Join(sitePackages, "requests-2.31.0.dist-info"),
+				License:      "Apache 2.0",
+				Classifiers:  []string{"License :: OSI Approved :: Apache Software License"},
+				LicenseFiles: []string{"LICENSE"},
+			},
+			{
+				Name:        "six",
+				Version:     "1.16.0",
+				MetadataDir: filepath.Join(sitePackages, "six-1.16.0.dist-info"),
+				LicenceDir:  filepath.Join(sitePackages, "six-1.16.0.dist-info"),
+				Classifiers: []string{"License :: OSI Approved :: MIT License", "License :: OSI Approved :: ISC License (ISCL)"},
+			},
+		}))
+	})
+
+	It("should declare the licence expression, then the licence classifiers, then the SPDX licence", func() {
+		// when
+		distributions, err := Distributions([]string{sitePackages})
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(distributions[0].DeclaredLicence()).To(Equal("MIT"))
+		Expect(distributions[1].DeclaredLicence()).To(BeEmpty())
+		Expect(distributions[2].DeclaredLicence()).To(BeEmpty())
+		Expect(distributions[3].DeclaredLicence()).To(Equal("(MIT OR ISC)"))
+	})
+
+	It("should prefer the licence classifiers to a licence which is not an SPDX identifier", func() {
+		gplClassifier := "License :: OSI Approved :: GNU General Public License v3 (GPLv3)"
+
+		Expect((&Distribution{License: "GPLv3", Classifiers: []string{gplClassifier}}).DeclaredLicence()).To(Equal("GPL-3.0-only"))
+		Expect((&Distribution{License: "GPLv3"}).DeclaredLicence()).To(BeEmpty())
+		Expect((&Distribution{License: "GPL-3.0"}).DeclaredLicence()).To(Equal("GPL-3.0"))
+		Expect((&Distribution{License: "MIT", Classifiers: []string{gplClassifier}}).DeclaredLicence()).To(Equal("GPL-3.0-only"))
+	})
+
+	It("should map the licence classifiers to SPDX licences", func() {
+		for classifier, licence := range classifierLicences {
+			Expect(detection.IsSPDXLicence(licence)).To(BeTrue(), "licence %s of classifier %s", licence, classifier)
+		}
+	})
+
+	It("should return the distributions whose metadata cannot be read with their error", func() {
+		// given
+		unreadable := filepath.Join("testdata", "unreadable")
+
+		// when
+		distributions, err := Distributions([]string{unreadable})
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(distributions).To(HaveLen(3))
+		Expect(distributions[0].String()).To(Equal("broken@1.0"))
+		Expect(distributions[0].Err).To(MatchError(ContainSubstring("invalid metadata line")))
+		Expect(distributions[1].String()).To(Equal("missing@2.0"))
+		Expect(distributions[1].LicenceDir).To(Equal(filepath.Join(unreadable, "missing-2.0.dist-info")))
+		Expect(distributions[1].Err).To(HaveOccurred())
+		Expect(distributions[2].String()).To(Equal("valid@3.0"))
+		Expect(distributions[2].Err).ToNot(HaveOccurred())
+		Expect(distributions[2].DeclaredLicence()).To(Equal("MIT"))
+	})
+})
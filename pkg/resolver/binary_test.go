@@ -0,0 +1,75 @@
+//go:build go1.18
+// +build go1.18
+
+package resolver
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"github.com/sky-uk/licence-compliance-checker/pkg/gomodules/gomodulestest"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("go executables", func() {
+	var binDir string
+	previousEnv := make(map[string]string)
+
+	BeforeEach(func() {
+		var err error
+		binDir, err = ioutil.TempDir("", "binaries")
+		Expect(err).ToNot(HaveOccurred())
+
+		modCache, err := filepath.Abs("../gomodules/testdata/modcache")
+		Expect(err).ToNot(HaveOccurred())
+		for key, value := range map[string]string{"GOMODCACHE": modCache, "GOPROXY": "off", "GOFLAGS": ""} {
+			previousEnv[key] = os.Getenv(key)
+			os.Setenv(key, value)
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(binDir)
+		for key, value := range previousEnv {
+			os.Setenv(key, value)
+		}
+	})
+
+	It("should resolve the dependencies of the executable from the module cache, without its devel main module", func() {
+		// given
+		binary, err := gomodulestest.BuildBinary("../gomodules/testdata/binary-module", filepath.Join(binDir, "binary-module"), "GO111MODULE=on", "GOFLAGS=-mod=vendor")
+		Expect(err).ToNot(HaveOccurred())
+
+		// when
+		projects, err := (&BinaryModules{Binary: binary}).Resolve()
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(projects).To(HaveLen(2))
+		Expect(projects[0].Project).To(Equal("example.com/dep@v1.2.0"))
+		Expect(projects[0].Directory).To(BeEmpty())
+		Expect(projects[0].ErrStr).ToNot(BeEmpty())
+		Expect(projects[1]).To(Equal(detection.Result{
+			Project:     "example.com/old@v1.0.0",
+			Ecosystem:   detection.EcosystemGo,
+			Module:      "example.com/old",
+			Version:     "v1.0.0",
+			Directory:   filepath.Join(os.Getenv("GOMODCACHE"), "github.com", "!some-!org", "original@v1.0.0"),
+			Replacement: &detection.Replacement{Module: "github.com/Some-Org/original", Version: "v1.0.0"},
+		}))
+	})
+
+	It("should fail for executables built outside of a go module", func() {
+		// given
+		binary, err := gomodulestest.BuildBinary("../gomodules/testdata/gopath-binary", filepath.Join(binDir, "gopath-binary"), "GO111MODULE=off")
+		Expect(err).ToNot(HaveOccurred())
+
+		// when
+		_, err = (&BinaryModules{Binary: binary}).Resolve()
+
+		// then
+		Expect(err).To(HaveOccurred())
+	})
+})
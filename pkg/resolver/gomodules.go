@@ -0,0 +1,264 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"github.com/sky-uk/licence-compliance-checker/pkg/gomodules"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GoModules resolves the go modules the project of the current directory depends on, as listed by `go list -m all`
+type GoModules struct {
+	// UseModuleZips detects licences straight from the module zips of the module download cache, whose hashes are
+	// checked against go.sum, rather than from the extracted modules
+	UseModuleZips bool
+	// FetchMissingModules fetches the modules which have not been downloaded from the file:// and http(s):// proxies of
+	// GOPROXY, rather than reporting them as unresolved
+	FetchMissingModules bool
+	// CheckReplacedModules detects the licence of the original modules of replace directives from the module cache,
+	// with LicenceDetector
+	CheckReplacedModules bool
+	LicenceDetector      detection.LicenceDetector
+}
+
+// Name describes the projects of the resolver
+func (g *GoModules) Name() string {
+	return "go modules"
+}
+
+// Resolve returns the go modules of the project, with their dependency type from the requirements of its go.mod. The
+// modules which cannot be found have an error. Unless they are fetched, the modules which have not been downloaded and
+// provide no package to the build, e.g. the modules only required by the go.mod of other modules, are skipped.
+func (g *GoModules) Resolve() ([]detection.Result, error) {
+	modules, err := gomodules.List("")
+	if err != nil {
+		return nil, err
+	}
+
+	var goSum gomodules.GoSum
+	var directRequirements map[string]bool
+	for _, module := range modules {
+		if !module.Main {
+			continue
+		}
+		if directRequirements, err = gomodules.DirectRequirements(filepath.Join(module.Dir, gomodules.GoMod)); err != nil {
+			return nil, err
+		}
+		if g.UseModuleZips || g.FetchMissingModules {
+			if goSum, err = gomodules.ReadGoSum(filepath.Join(module.Dir, "go.sum")); err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+		}
+	}
+
+	var packageModules map[string]bool
+	var projects []detection.Result
+	for _, module := range modules {
+		project := moduleProject(module)
+		project.Dependency = dependencyType(module, directRequirements)
+		switch {
+		case module.Main || module.IsLocalReplacement():
+			if module.Dir == "" {
+				project.ErrStr = "module directory not found"
+			}
+		case g.UseModuleZips:
+			if project.Directory, err = g.moduleZip(module, goSum); err != nil {
+				project.ErrStr = err.Error()
+			}
+		case module.Dir == "":
+			if !g.FetchMissingModules {
+				if packageModules == nil {
+					if packageModules, err = gomodules.PackageModules("", modules); err != nil {
+						return nil, fmt.Errorf("unable to list the packages of the go modules: %v", err)
+					}
+				}
+				if !packageModules[module.Path] {
+					log.Debugf("Skipping go module %s which has not been downloaded, and provides no package to the build", module.String())
+					continue
+				}
+				project.ErrStr = "module has not been downloaded"
+				break
+			}
+			if project.Directory, err = g.moduleZip(module, goSum); err != nil {
+				project.ErrStr = err.Error()
+			}
+		}
+
+		if module.Replace != nil && g.CheckReplacedModules {
+			project.Replacement.OriginalMatches, err = detectOriginalModuleLicence(g.LicenceDetector, module)
+			if err != nil {
+				log.Warnf("Unable to detect the licence of replaced module %s@%s: %v", module.Path, module.Version, err)
+			}
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// moduleZip returns the zip of the module, or of its replacement, from the module download cache or when allowed from GOPROXY.
+// The zip hash is checked against go.sum.
+func (g *GoModules) moduleZip(module gomodules.Module, goSum gomodules.GoSum) (string, error) {
+	zipModule := module
+	if module.Replace != nil {
+		zipModule = *module.Replace
+	}
+
+	zipPath, err := gomodules.CacheZip(zipModule.Path, zipModule.Version)
+	if err != nil && g.FetchMissingModules {
+		zipPath, err = gomodules.ProxyZip(moduleProxies(g.FetchMissingModules), "", zipModule.Path, zipModule.Version)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	err = goSum.VerifyZip(zipPath, zipModule.Path, zipModule.Version)
+	if err == gomodules.ErrMissingFromGoSum {
+		log.Warnf("Unable to verify zip of module %s: %v", zipModule.String(), err)
+	} else if err != nil {
+		return "", err
+	}
+	return zipPath, nil
+}
+
+// VendoredModules resolves the go modules listed in the modules.txt of a vendor directory, as created by `go mod vendor`
+type VendoredModules struct {
+	VendorDir string
+	// UnlistedDirs are the vendor directories not belonging to any module of modules.txt, once resolved
+	UnlistedDirs []string
+}
+
+// Name describes the projects of the resolver
+func (v *VendoredModules) Name() string {
+	return "vendored go modules"
+}
+
+// Resolve returns the vendored go modules, with their dependency type from the requirements of the go.mod next to the
+// vendor directory
+func (v *VendoredModules) Resolve() ([]detection.Result, error) {
+	modules, err := gomodules.VendoredModules(v.VendorDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.UnlistedDirs, err = gomodules.UnlistedVendorDirs(v.VendorDir, modules); err != nil {
+		return nil, err
+	}
+
+	// the vendored modules are only known to be direct or indirect dependencies from the go.mod next to the vendor directory
+	directRequirements, err := gomodules.DirectRequirements(filepath.Join(filepath.Dir(v.VendorDir), gomodules.GoMod))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var projects []detection.Result
+	for _, module := range modules {
+		project := moduleProject(module)
+		project.Dependency = dependencyType(module, directRequirements)
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// BinaryModules resolves the go modules embedded in the build information of a go executable. Each module is looked
+// up in the module cache, then in the file:// directories of GOPROXY.
+type BinaryModules struct {
+	Binary string
+	// FetchMissingModules also fetches the modules which cannot be found from the http(s):// proxies of GOPROXY
+	FetchMissingModules bool
+}
+
+// Name describes the projects of the resolver
+func (b *BinaryModules) Name() string {
+	return "go modules of binary " + b.Binary
+}
+
+// Resolve returns the go modules of the binary, except its main module when it has no released version
+func (b *BinaryModules) Resolve() ([]detection.Result, error) {
+	modules, err := gomodules.BinaryModules(b.Binary)
+	if err != nil {
+		return nil, err
+	}
+
+	proxies := moduleProxies(b.FetchMissingModules)
+
+	var projects []detection.Result
+	for _, module := range modules {
+		if module.Main && (module.Version == "" || module.Version == "(devel)") {
+			log.Infof("Skipping main module %s of %s, which has no released version", module.Path, b.Binary)
+			continue
+		}
+
+		project := moduleProject(module)
+		if module.IsLocalReplacement() {
+			project.ErrStr = fmt.Sprintf("module is replaced by local directory %s, which is not available", module.Replace.Path)
+		} else {
+			located := module
+			if module.Replace != nil {
+				located = *module.Replace
+			}
+			if project.Directory, err = gomodules.Locate(located.Path, located.Version, located.Sum, proxies, ""); err != nil {
+				project.ErrStr = err.Error()
+			}
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// moduleProxies returns the GOPROXY proxies to look up modules into.
+// Only the file-based proxies are used unless fetching missing modules is allowed.
+func moduleProxies(fetchMissingModules bool) []string {
+	var proxies []string
+	for _, proxy := range gomodules.Proxies() {
+		if fetchMissingModules || strings.HasPrefix(proxy, "file://") {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+func moduleProject(module gomodules.Module) detection.Result {
+	project := detection.Result{
+		Project:   module.String(),
+		Ecosystem: detection.EcosystemGo,
+		Module:    module.Path,
+		Version:   module.Version,
+		Directory: module.Dir,
+	}
+	if module.Replace != nil {
+		project.Replacement = &detection.Replacement{Module: module.Replace.Path, Version: module.Replace.Version}
+	}
+	return project
+}
+
+// dependencyType returns whether the module is a direct or an indirect dependency of the main module, given the modules
+// the main module directly requires. It is unknown for the main module itself and when the requirements are not known.
+func dependencyType(module gomodules.Module, directRequirements map[string]bool) string {
+	if module.Main || directRequirements == nil {
+		return ""
+	}
+	if directRequirements[module.Path] && !module.Indirect {
+		return detection.DependencyDirect
+	}
+	return detection.DependencyIndirect
+}
+
+func detectOriginalModuleLicence(licenceDetector detection.LicenceDetector, module gomodules.Module) ([]detection.LicenceMatch, error) {
+	dir, err := gomodules.CacheDir(module.Path, module.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := licenceDetector.Detect([]string{dir})
+	if err != nil {
+		return nil, err
+	}
+	if results[0].ErrStr != "" {
+		return nil, errors.New(results[0].ErrStr)
+	}
+	return results[0].Matches, nil
+}
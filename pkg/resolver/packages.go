@@ -0,0 +1,279 @@
+package resolver
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/sky-uk/licence-compliance-checker/pkg/cargo"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"github.com/sky-uk/licence-compliance-checker/pkg/maven"
+	"github.com/sky-uk/licence-compliance-checker/pkg/npm"
+	"github.com/sky-uk/licence-compliance-checker/pkg/ospackages"
+	"github.com/sky-uk/licence-compliance-checker/pkg/python"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NpmPackages resolves the npm packages locked in the package-lock.json of a directory, from its node_modules directory
+type NpmPackages struct {
+	ProjectDir string
+}
+
+// Name describes the projects of the resolver
+func (n *NpmPackages) Name() string {
+	return "npm packages of " + n.ProjectDir
+}
+
+// Resolve returns the locked npm packages, skipping the optional packages which have not been installed
+func (n *NpmPackages) Resolve() ([]detection.Result, error) {
+	packages, err := npm.Packages(n.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []detection.Result
+	for _, pkg := range packages {
+		if !pkg.Installed() && pkg.Optional {
+			log.Debugf("Skipping optional npm package %s which has not been installed", pkg.String())
+			continue
+		}
+
+		project := detection.Result{
+			Project:         pkg.String(),
+			Ecosystem:       detection.EcosystemNpm,
+			Module:          pkg.Name,
+			Version:         pkg.Version,
+			Directory:       pkg.Dir,
+			Dependency:      detection.DependencyIndirect,
+			Scope:           detection.ScopeProd,
+			DeclaredLicence: pkg.License,
+		}
+		if pkg.Direct {
+			project.Dependency = detection.DependencyDirect
+		}
+		if pkg.Dev {
+			project.Scope = detection.ScopeDev
+		}
+		if !pkg.Installed() {
+			project.ErrStr = "package is not installed in node_modules"
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// PythonPackages resolves the python distributions installed into a site-packages or virtualenv directory
+type PythonPackages struct {
+	Dir string
+}
+
+// Name describes the projects of the resolver
+func (p *PythonPackages) Name() string {
+	return "python packages of " + p.Dir
+}
+
+// Resolve returns the installed python distributions, whose licence is detected from their metadata directory.
+// Distributions whose metadata cannot be read are unresolved.
+func (p *PythonPackages) Resolve() ([]detection.Result, error) {
+	sitePackages, err := python.SitePackages(p.Dir)
+	if err != nil {
+		return nil, err
+	}
+	distributions, err := python.Distributions(sitePackages)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []detection.Result
+	for _, distribution := range distributions {
+		project := detection.Result{
+			Project:         distribution.String(),
+			Ecosystem:       detection.EcosystemPython,
+			Module:          distribution.Name,
+			Version:         distribution.Version,
+			Directory:       distribution.LicenceDir,
+			DeclaredLicence: distribution.DeclaredLicence(),
+		}
+		if distribution.Err != nil {
+			project.ErrStr = fmt.Sprintf("unable to read distribution metadata: %v", distribution.Err)
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// MavenDependencies resolves the maven artifacts of a pom.xml (direct dependencies) or of a dependency list (as
+// written by `mvn dependency:list -DoutputFile=<file>`), from a local maven repository
+type MavenDependencies struct {
+	DependenciesPath string
+	// Repository is the local maven repository, ~/.m2/repository when empty
+	Repository string
+}
+
+// Name describes the projects of the resolver
+func (m *MavenDependencies) Name() string {
+	return "maven dependencies of " + m.DependenciesPath
+}
+
+// Resolve returns the maven artifacts, whose licence is detected from their jar
+func (m *MavenDependencies) Resolve() ([]detection.Result, error) {
+	repository := m.Repository
+	if repository == "" {
+		var err error
+		if repository, err = maven.LocalRepository(); err != nil {
+			return nil, err
+		}
+	}
+
+	var artifacts []maven.Artifact
+	var err error
+	isPom := strings.EqualFold(filepath.Ext(m.DependenciesPath), ".xml") || strings.EqualFold(filepath.Ext(m.DependenciesPath), ".pom")
+	if isPom {
+		artifacts, err = maven.ReadPomDependencies(m.DependenciesPath, repository)
+	} else {
+		artifacts, err = maven.ReadDependencyList(m.DependenciesPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []detection.Result
+	for _, artifact := range artifacts {
+		project := detection.Result{
+			Project:   artifact.String(),
+			Ecosystem: detection.EcosystemMaven,
+			Module:    artifact.Key(),
+			Version:   artifact.Version,
+			Directory: artifact.File(repository),
+			Scope:     detection.ScopeProd,
+		}
+		if artifact.Scope == "test" {
+			project.Scope = detection.ScopeDev
+		}
+		if isPom {
+			project.Dependency = detection.DependencyDirect
+		}
+
+		if artifact.Version == "" {
+			project.ErrStr = "artifact version cannot be resolved from the POM and its parents"
+			projects = append(projects, project)
+			continue
+		}
+		if artifact.Type == "pom" {
+			// pom artifacts have no files, only their declared licences
+			project.Directory = artifact.Dir(repository)
+		}
+		if _, err := os.Stat(project.Directory); err != nil {
+			project.ErrStr = fmt.Sprintf("artifact not found in local maven repository: %v", err)
+			projects = append(projects, project)
+			continue
+		}
+
+		licences, err := maven.DeclaredLicences(artifact, repository)
+		if err != nil {
+			log.Warnf("Unable to read the declared licences of maven artifact %s: %v", artifact.String(), err)
+		}
+		if len(licences) > 1 {
+			// a POM listing several licences lets users choose any of them
+			project.DeclaredLicence = "(" + strings.Join(licences, " OR ") + ")"
+		} else {
+			project.DeclaredLicence = strings.Join(licences, "")
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// CargoCrates resolves the rust crates locked in a Cargo.lock, or in the Cargo.lock of a directory, from the cargo
+// registry cache of CARGO_HOME
+type CargoCrates struct {
+	LockPath string
+}
+
+// Name describes the projects of the resolver
+func (c *CargoCrates) Name() string {
+	return "cargo crates of " + c.LockPath
+}
+
+// Resolve returns the locked crates, skipping the crates of the project itself
+func (c *CargoCrates) Resolve() ([]detection.Result, error) {
+	lockPath := c.LockPath
+	if info, err := os.Stat(lockPath); err == nil && info.IsDir() {
+		lockPath = filepath.Join(lockPath, cargo.LockFile)
+	}
+	lock, err := cargo.ReadLock(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	cargoHome, err := cargo.Home()
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []detection.Result
+	for _, crate := range lock.Crates {
+		if crate.IsLocal() {
+			log.Debugf("Skipping crate %s of the project itself", crate.String())
+			continue
+		}
+
+		project := detection.Result{
+			Project:    crate.String(),
+			Ecosystem:  detection.EcosystemCargo,
+			Module:     crate.Name,
+			Version:    crate.Version,
+			Dependency: detection.DependencyIndirect,
+		}
+		if lock.IsDirect(crate) {
+			project.Dependency = detection.DependencyDirect
+		}
+
+		if project.Directory, err = cargo.SourceDir(cargoHome, crate); err != nil {
+			project.ErrStr = err.Error()
+			projects = append(projects, project)
+			continue
+		}
+		if project.DeclaredLicence, project.LicenceFile, err = cargo.ManifestLicence(project.Directory); err != nil {
+			log.Warnf("Unable to read the declared licence of crate %s: %v", crate.String(), err)
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// OSPackages resolves the dpkg and apk packages installed in a root filesystem directory or image tarball
+type OSPackages struct {
+	RootFS string
+}
+
+// Name describes the projects of the resolver
+func (o *OSPackages) Name() string {
+	return "OS packages of " + o.RootFS
+}
+
+// Resolve returns the installed OS packages with their declared licence, as they have no directory
+func (o *OSPackages) Resolve() ([]detection.Result, error) {
+	packages, err := ospackages.Packages(o.RootFS)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []detection.Result
+	for _, pkg := range packages {
+		project := detection.Result{
+			Project:         pkg.String(),
+			Ecosystem:       detection.EcosystemDeb,
+			Module:          pkg.Name,
+			Version:         pkg.Version,
+			DeclaredLicence: pkg.DeclaredLicence(),
+		}
+		if pkg.Manager == ospackages.ManagerApk {
+			project.Ecosystem = detection.EcosystemApk
+		}
+		if project.DeclaredLicence == "" && pkg.CopyrightFile != "" {
+			log.Debugf("Copyright file %s of OS package %s is not machine-readable", pkg.CopyrightFile, pkg.String())
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
@@ -0,0 +1,80 @@
+package resolver
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+)
+
+// Resolver resolves the projects of a dependency source, e.g. the go modules of a project or the packages locked in a
+// lock file, so that their licence can be checked.
+//
+// The projects are detection results without licence matches: their identity (Project, Ecosystem, Module and
+// Version), the Directory their licence is detected from, and optionally their DeclaredLicence, Dependency type and
+// Scope. Projects which cannot be found, e.g. not downloaded, are returned with their ErrStr set so that they are
+// reported as unresolved, rather than failing the whole resolution.
+type Resolver interface {
+	// Name describes the projects of the resolver in logs and errors, e.g. "go modules"
+	Name() string
+	// Resolve returns the projects of the dependency source
+	Resolve() ([]detection.Result, error)
+}
+
+// Resolvers resolves the projects of several resolvers, in the order they are registered
+type Resolvers struct {
+	resolvers []Resolver
+}
+
+// Register adds a resolver whose projects are merged with the projects of the other resolvers
+func (r *Resolvers) Register(resolver Resolver) {
+	r.resolvers = append(r.resolvers, resolver)
+}
+
+// Len returns the number of registered resolvers
+func (r *Resolvers) Len() int {
+	return len(r.resolvers)
+}
+
+// Resolve returns the projects of all the registered resolvers. A project resolved by several resolvers, i.e. with the
+// same ecosystem and identity, is only kept from the first resolver.
+func (r *Resolvers) Resolve() ([]detection.Result, error) {
+	var projects []detection.Result
+	resolved := make(map[string]string)
+	for _, resolver := range r.resolvers {
+		resolverProjects, err := resolver.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %v", resolver.Name(), err)
+		}
+		log.Infof("Found %s: %v", resolver.Name(), resolverProjects)
+
+		for _, project := range resolverProjects {
+			key := project.Ecosystem + ":" + project.Project
+			if firstResolver, ok := resolved[key]; ok {
+				log.Debugf("Skipping project %s of %s, already found in %s", project.Project, resolver.Name(), firstResolver)
+				continue
+			}
+			resolved[key] = resolver.Name()
+			projects = append(projects, project)
+		}
+	}
+	return projects, nil
+}
+
+// Directories resolves projects from their directory, e.g. vendor/github.com/spf13/cobra, without further details
+type Directories struct {
+	Paths []string
+}
+
+// Name describes the projects of the resolver
+func (d *Directories) Name() string {
+	return "project directories"
+}
+
+// Resolve returns a project per directory, identified by its path
+func (d *Directories) Resolve() ([]detection.Result, error) {
+	var projects []detection.Result
+	for _, path := range d.Paths {
+		projects = append(projects, detection.Result{Project: path})
+	}
+	return projects, nil
+}
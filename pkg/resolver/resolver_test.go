@@ -0,0 +1,141 @@
+package resolver
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestResolver(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/resolver.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Resolver Suite", []Reporter{junitReporter})
+}
+
+var _ = Describe("resolvers", func() {
+
+	It("should merge the projects of the registered resolvers in order", func() {
+		// given
+		var resolvers Resolvers
+		resolvers.Register(&Directories{Paths: []string{"vendor/a", "vendor/b"}})
+		resolvers.Register(&fakeResolver{name: "npm packages", projects: []detection.Result{
+			{Project: "left-pad@1.3.0", Ecosystem: detection.EcosystemNpm, Directory: "node_modules/left-pad"},
+		}})
+
+		// when
+		projects, err := resolvers.Resolve()
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resolvers.Len()).To(Equal(2))
+		Expect(projects).To(Equal([]detection.Result{
+			{Project: "vendor/a"},
+			{Project: "vendor/b"},
+			{Project: "left-pad@1.3.0", Ecosystem: detection.EcosystemNpm, Directory: "node_modules/left-pad"},
+		}))
+	})
+
+	It("should keep projects found by several resolvers from the first resolver", func() {
+		// given
+		var resolvers Resolvers
+		resolvers.Register(&fakeResolver{name: "go modules", projects: []detection.Result{
+			{Project: "example.com/a@v1.0.0", Ecosystem: detection.EcosystemGo, Directory: "first"},
+		}})
+		resolvers.Register(&fakeResolver{name: "other go modules", projects: []detection.Result{
+			{Project: "example.com/a@v1.0.0", Ecosystem: detection.EcosystemGo, Directory: "second"},
+			{Project: "example.com/a@v1.0.0", Ecosystem: detection.EcosystemNpm, Directory: "npm"},
+		}})
+
+		// when
+		projects, err := resolvers.Resolve()
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(projects).To(HaveLen(2))
+		Expect(projects[0].Directory).To(Equal("first"))
+		Expect(projects[1].Directory).To(Equal("npm"))
+	})
+
+	It("should fail with the name of the resolver which cannot resolve its projects", func() {
+		// given
+		var resolvers Resolvers
+		resolvers.Register(&fakeResolver{name: "go modules", err: errors.New("not using modules")})
+
+		// when
+		_, err := resolvers.Resolve()
+
+		// then
+		Expect(err).To(MatchError("failed to list go modules: not using modules"))
+	})
+
+	Context("go modules", func() {
+		var previousDir string
+		previousEnv := make(map[string]string)
+
+		BeforeEach(func() {
+			modCache, err := filepath.Abs("testdata/modcache")
+			Expect(err).ToNot(HaveOccurred())
+			for key, value := range map[string]string{"GOMODCACHE": modCache, "GOPROXY": "off", "GOFLAGS": "", "GO111MODULE": "on"} {
+				previousEnv[key] = os.Getenv(key)
+				os.Setenv(key, value)
+			}
+			previousDir, err = os.Getwd()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.Chdir("testdata/missing-module")).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.Chdir(previousDir)
+			for key, value := range previousEnv {
+				os.Setenv(key, value)
+			}
+		})
+
+		It("should report the modules providing packages which have not been downloaded as unresolved, and skip the others", func() {
+			// when
+			projects, err := (&GoModules{}).Resolve()
+
+			// then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(projects).To(HaveLen(2))
+			Expect(projects[0].Module).To(Equal("example.com/missing-module"))
+			Expect(projects[0].ErrStr).To(BeEmpty())
+			Expect(projects[1]).To(Equal(detection.Result{
+				Project:    "example.com/missing@v1.0.0",
+				Ecosystem:  detection.EcosystemGo,
+				Module:     "example.com/missing",
+				Version:    "v1.0.0",
+				Dependency: detection.DependencyDirect,
+				ErrStr:     "module has not been downloaded",
+			}))
+		})
+	})
+
+})
+
+type fakeResolver struct {
+	name     string
+	projects []detection.Result
+	err      error
+}
+
+func (f *fakeResolver) Name() string {
+	return f.name
+}
+
+func (f *fakeResolver) Resolve() ([]detection.Result, error) {
+	return f.projects, f.err
+}
@@ -0,0 +1,8 @@
+module example.com/missing-module
+
+go 1.12
+
+require (
+	example.com/missing v1.0.0
+	example.com/unused v1.0.0
+)
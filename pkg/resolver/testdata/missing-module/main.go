@@ -0,0 +1,5 @@
+package main
+
+import _ "example.com/missing/lib"
+
+func main() {}
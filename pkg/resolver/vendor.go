@@ -0,0 +1,76 @@
+package resolver
+
+import (
+	"fmt"
+	"github.com/sky-uk/licence-compliance-checker/pkg/dep"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"github.com/sky-uk/licence-compliance-checker/pkg/vendordir"
+	"os"
+)
+
+// DepProjects resolves the projects locked in a Gopkg.lock, as managed by `go dep`, from their vendor directory
+type DepProjects struct {
+	LockFile  string
+	VendorDir string
+}
+
+// Name describes the projects of the resolver
+func (d *DepProjects) Name() string {
+	return "dep projects"
+}
+
+// Resolve returns the locked projects, which are direct dependencies when imported by the input imports of the lock
+func (d *DepProjects) Resolve() ([]detection.Result, error) {
+	lock, err := dep.ReadLock(d.LockFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []detection.Result
+	for _, depProject := range lock.Projects {
+		project := detection.Result{
+			Project:   depProject.String(),
+			Ecosystem: detection.EcosystemGo,
+			Module:    depProject.Name,
+			Version:   depProject.VersionOrRevision(),
+			Directory: depProject.Dir(d.VendorDir),
+		}
+		if lock.IsDirect(depProject) {
+			project.Dependency = detection.DependencyDirect
+		} else {
+			project.Dependency = detection.DependencyIndirect
+		}
+		if _, err := os.Stat(project.Directory); err != nil {
+			project.ErrStr = fmt.Sprintf("project is not vendored: %v", err)
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// VendorDirProjects resolves the repository roots found in a vendor directory, without any lock file
+type VendorDirProjects struct {
+	VendorDir string
+	// OrphanedDirs are the vendor directories with neither a licence nor a known repository root, once resolved
+	OrphanedDirs []string
+}
+
+// Name describes the projects of the resolver
+func (v *VendorDirProjects) Name() string {
+	return "vendored projects"
+}
+
+// Resolve returns a project per repository root, identified by its directory
+func (v *VendorDirProjects) Resolve() ([]detection.Result, error) {
+	roots, orphanedDirs, err := vendordir.Discover(v.VendorDir)
+	if err != nil {
+		return nil, err
+	}
+	v.OrphanedDirs = orphanedDirs
+
+	var projects []detection.Result
+	for _, root := range roots {
+		projects = append(projects, detection.Result{Project: root.Dir, Ecosystem: detection.EcosystemGo, Module: root.ImportPath})
+	}
+	return projects, nil
+}
@@ -0,0 +1,39 @@
+// Package toml parses the TOML values written by dep and cargo into their lock files and manifests, i.e. strings and
+// arrays of strings on a single line
+package toml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseString parses a basic string, e.g. `"a\tb"`, or a literal string, e.g. `'a\b'`
+func ParseString(value string) (string, error) {
+	if strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") && len(value) >= 2 {
+		// literal strings have no escapes
+		return value[1 : len(value)-1], nil
+	}
+	return strconv.Unquote(value)
+}
+
+// ParseStrings parses an array of strings, e.g. `["a", "b",]`
+func ParseStrings(value string) ([]string, error) {
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("invalid array %s", value)
+	}
+
+	var values []string
+	for _, element := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"), ",") {
+		element = strings.TrimSpace(element)
+		if element == "" {
+			continue
+		}
+		s, err := ParseString(element)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s in array: %v", element, err)
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
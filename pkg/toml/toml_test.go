@@ -0,0 +1,42 @@
+package toml
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestToml(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/toml.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Toml Suite", []Reporter{junitReporter})
+}
+
+var _ = Describe("TOML values", func() {
+
+	It("should parse basic and literal strings", func() {
+		Expect(ParseString(`"a\tb"`)).To(Equal("a\tb"))
+		Expect(ParseString(`'a\b'`)).To(Equal(`a\b`))
+	})
+
+	It("should parse arrays of strings with a trailing comma", func() {
+		Expect(ParseStrings(`["a", 'b',]`)).To(Equal([]string{"a", "b"}))
+		Expect(ParseStrings(`[]`)).To(BeEmpty())
+	})
+
+	It("should fail on invalid arrays of strings", func() {
+		_, err := ParseStrings(`["a", "b"`)
+		Expect(err).To(MatchError(ContainSubstring("invalid array")))
+		_, err = ParseStrings(`["a", 1]`)
+		Expect(err).To(MatchError(ContainSubstring("invalid string 1 in array")))
+	})
+})
@@ -0,0 +1,122 @@
+package vendordir
+
+import (
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// gopkgInVersionRe matches the last element of gopkg.in import paths, e.g. yaml.v2
+var gopkgInVersionRe = regexp.MustCompile(`\.v\d+$`)
+
+// hostRootDepths is the number of import path elements of the repository roots for known hosts,
+// e.g. 3 for github.com/owner/repo
+var hostRootDepths = map[string]int{
+	"github.com":        3,
+	"gitlab.com":        3,
+	"bitbucket.org":     3,
+	"golang.org":        3,
+	"honnef.co":         3,
+	"google.golang.org": 2,
+	"cloud.google.com":  2,
+	"go.uber.org":       2,
+	"k8s.io":            2,
+	"sigs.k8s.io":       2,
+	"go.etcd.io":        2,
+	"go.opencensus.io":  1,
+	"go.mongodb.org":    2,
+	"gonum.org":         3,
+}
+
+// Root is a repository root found in a vendor directory
+type Root struct {
+	// ImportPath is the import path of the repository root, e.g. github.com/spf13/cobra
+	ImportPath string
+	// Dir is the directory of the repository root within the vendor directory
+	Dir string
+}
+
+// Discover walks the vendor directory and returns the repository roots it contains, along with the orphaned
+// directories, which contain files but neither belong to a repository root nor have a licence file.
+// Repository roots are found from the layout of known hosts, e.g. github.com/owner/repo, and otherwise are the first
+// directories with a licence file.
+func Discover(vendorDir string) ([]Root, []string, error) {
+	var roots []Root
+	orphans := make(map[string]bool)
+	err := filepath.Walk(vendorDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == vendorDir {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(vendorDir, path)
+		if err != nil {
+			return err
+		}
+		elements := strings.Split(filepath.ToSlash(relPath), "/")
+
+		if !info.IsDir() {
+			if len(elements) > 1 {
+				orphans[filepath.Dir(path)] = true
+			}
+			return nil
+		}
+		if strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		isRoot, err := isRepositoryRoot(path, elements)
+		if err != nil {
+			return err
+		}
+		if isRoot {
+			roots = append(roots, Root{ImportPath: strings.Join(elements, "/"), Dir: path})
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var orphanDirs []string
+	for dir := range orphans {
+		orphanDirs = append(orphanDirs, dir)
+	}
+	sort.Strings(orphanDirs)
+	return roots, orphanDirs, nil
+}
+
+// isRepositoryRoot returns true when the directory with the given import path elements is a repository root,
+// according to the layout of its host when known, or else to the presence of a licence file
+func isRepositoryRoot(dir string, elements []string) (bool, error) {
+	host := elements[0]
+	if host == "gopkg.in" {
+		// gopkg.in/pkg.v1 or gopkg.in/user/pkg.v1
+		last := elements[len(elements)-1]
+		return len(elements) <= 3 && len(elements) > 1 && gopkgInVersionRe.MatchString(last), nil
+	}
+	if depth, ok := hostRootDepths[host]; ok {
+		return len(elements) == depth, nil
+	}
+	return hasLicenceFile(dir)
+}
+
+func hasLicenceFile(dir string) (bool, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range files {
+		if !f.IsDir() && detection.IsLicenceFile(f.Name()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,60 @@
+package vendordir
+
+import (
+	"flag"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/reporters"
+	. "github.com/onsi/gomega"
+	"path/filepath"
+	"testing"
+)
+
+var junitReportDir string
+
+func init() {
+	flag.StringVar(&junitReportDir, "junit-report-dir", ".", "path to the directory that will contain the test reports")
+}
+
+func TestVendorDir(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter(fmt.Sprintf("%s/vendordir.xml", junitReportDir))
+	RunSpecsWithDefaultAndCustomReporters(t, "Vendor Directory Suite", []Reporter{junitReporter})
+}
+
+var _ = Describe("vendor directory discovery", func() {
+
+	It("should find the repository roots from known host layouts and licence files", func() {
+		// when
+		roots, _, err := Discover("testdata/vendor")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(roots).To(Equal([]Root{
+			{ImportPath: "example.com/lib", Dir: filepath.Join("testdata", "vendor", "example.com", "lib")},
+			{ImportPath: "github.com/owner/repo", Dir: filepath.Join("testdata", "vendor", "github.com", "owner", "repo")},
+			{ImportPath: "gopkg.in/user/pkg.v1", Dir: filepath.Join("testdata", "vendor", "gopkg.in", "user", "pkg.v1")},
+			{ImportPath: "gopkg.in/yaml.v2", Dir: filepath.Join("testdata", "vendor", "gopkg.in", "yaml.v2")},
+		}))
+	})
+
+	It("should find the orphaned directories with neither a licence nor a known repository root", func() {
+		// when
+		_, orphans, err := Discover("testdata/vendor")
+
+		// then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(orphans).To(Equal([]string{
+			filepath.Join("testdata", "vendor", "example.com", "nolicence"),
+			filepath.Join("testdata", "vendor", "github.com", "owner"),
+		}))
+	})
+
+	It("should fail when the vendor directory does not exist", func() {
+		// when
+		_, _, err := Discover("testdata/does-not-exist")
+
+		// then
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,6 +1,8 @@
 package e2e
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -8,7 +10,9 @@ import (
 	"github.com/onsi/ginkgo/reporters"
 	. "github.com/onsi/gomega"
 	"github.com/sky-uk/licence-compliance-checker/pkg/compliance"
+	"github.com/sky-uk/licence-compliance-checker/pkg/detection"
 	"go/build"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -70,6 +74,21 @@ var _ = Describe("License Compliance Checker", func() {
 		Expect(results.Unidentifiable).To(BeNil())
 	})
 
+	It("should report the licence file each licence is found in as evidence", func() {
+		output, err := exec.Command(commandPath, "-A", "-r", "MIT", "testdata/MIT").CombinedOutput()
+		Expect(err).To(HaveOccurred())
+
+		licenceText, err := ioutil.ReadFile("testdata/MIT/LICENSE")
+		Expect(err).NotTo(HaveOccurred())
+		sum := sha256.Sum256(licenceText)
+
+		results := resultsFromJSON(string(output))
+		Expect(results.Restricted).To(HaveLen(1))
+		Expect(results.Restricted[0].Matches[0].Licence).To(Equal("MIT"))
+		Expect(results.Restricted[0].Matches[0].Detector).To(Equal("files"))
+		Expect(results.Restricted[0].Matches[0].Evidence).To(Equal([]detection.Evidence{{Path: "LICENSE", SHA256: hex.EncodeToString(sum[:])}}))
+	})
+
 	It("should fail when project does not have license file", func() {
 		output, err := exec.Command(commandPath, "-A", "-r", "MIT", "testdata/no-licence").CombinedOutput()
 		Expect(err).To(HaveOccurred())
@@ -79,6 +98,79 @@ var _ = Describe("License Compliance Checker", func() {
 		Expect(results.Unidentifiable[0].Project).To(Equal("testdata/no-licence"))
 	})
 
+	It("should detect the licence of projects without licence file from their SPDX headers", func() {
+		output, err := exec.Command(commandPath, "-A", "-r", "Apache-2.0", "--detect-spdx-headers", "testdata/spdx-headers", "testdata/MIT").CombinedOutput()
+		Expect(err).To(HaveOccurred())
+
+		results := resultsFromJSON(string(output))
+		Expect(results.Restricted).To(HaveLen(1))
+		Expect(results.Restricted[0].Project).To(Equal("testdata/spdx-headers"))
+		Expect(results.Restricted[0].Matches[0].Licence).To(Equal("Apache-2.0"))
+		Expect(results.Restricted[0].Matches[0].Detector).To(Equal("spdx-headers"))
+		Expect(results.Restricted[0].DifferingFiles).To(Equal([]detection.FileLicence{{Path: "lib/parser.c", Licence: "GPL-2.0-only", Evidence: "spdx-header"}}))
+		Expect(results.Compliant).To(HaveLen(1))
+		Expect(results.Compliant[0].Matches[0].Detector).To(Equal("files"))
+	})
+
+	It("should find projects with files of a restricted licence with a deep scan", func() {
+		output, err := exec.Command(commandPath, "-A", "-r", "GPL-2.0-or-later", "--deep-scan", "testdata/deep-scan", "testdata/BSD3").CombinedOutput()
+		Expect(err).To(HaveOccurred())
+
+		results := resultsFromJSON(string(output))
+		Expect(results.Restricted).To(HaveLen(1))
+		Expect(results.Restricted[0].Project).To(Equal("testdata/deep-scan"))
+		Expect(results.Restricted[0].Matches[0].Licence).To(Equal("MIT"))
+		Expect(results.Restricted[0].DifferingFiles).To(Equal([]detection.FileLicence{{Path: "src/parser.c", Licence: "GPL-2.0-or-later", Evidence: "notice", Restricted: true}}))
+		Expect(results.Compliant).To(HaveLen(1))
+		Expect(results.Compliant[0].Project).To(Equal("testdata/BSD3"))
+	})
+
+	It("should extract the copyright holders and years of projects", func() {
+		output, err := exec.Command(commandPath, "-A", "-r", "GPL-3.0", "--extract-copyrights", "testdata/copyrights").CombinedOutput()
+		Expect(err).NotTo(HaveOccurred())
+
+		results := resultsFromJSON(string(output))
+		Expect(results.Compliant).To(HaveLen(1))
+		Expect(results.Compliant[0].Copyrights).To(Equal([]detection.Copyright{
+			{Holder: "Example Corp", Years: "2016-2019"},
+			{Holder: "Jane Doe", Years: "2020"},
+		}))
+	})
+
+	It("should identify the licences of the licence templates of the policy", func() {
+		output, err := exec.Command(commandPath, "-A", "-r", "LicenseRef-Example-Internal", "--policy", "testdata/templates/policy.json", "testdata/templates/internal", "testdata/MIT").CombinedOutput()
+		Expect(err).To(HaveOccurred())
+
+		results := resultsFromJSON(string(output))
+		Expect(results.Restricted).To(HaveLen(1))
+		Expect(results.Restricted[0].Project).To(Equal("testdata/templates/internal"))
+		Expect(results.Restricted[0].Matches[0].Licence).To(Equal("LicenseRef-Example-Internal"))
+		Expect(results.Compliant).To(HaveLen(1))
+		Expect(results.Compliant[0].Project).To(Equal("testdata/MIT"))
+	})
+
+	It("should identify the licences of an imported SPDX licence list", func() {
+		cacheDir, err := ioutil.TempDir("", "licence-list")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(cacheDir)
+
+		output, err := exec.Command(commandPath, "licence-list", "import", "testdata/licence-list/license-list-data", "--licence-list-cache", cacheDir).CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(output))
+		Expect(string(output)).To(ContainSubstring("Imported SPDX licence list 3.99 released 2026-09-30"))
+
+		output, err = exec.Command(commandPath, "licence-list", "version", "--licence-list-cache", cacheDir).CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(output))
+		Expect(string(output)).To(ContainSubstring("SPDX licence list 3.99 released 2026-09-30"))
+
+		output, err = exec.Command(commandPath, "-A", "-r", "Example-New-1.0", "--licence-list-cache", cacheDir, "testdata/licence-list/example").CombinedOutput()
+		Expect(err).To(HaveOccurred())
+
+		results := resultsFromJSON(string(output))
+		Expect(results.LicenceListVersion).To(Equal("3.99"))
+		Expect(results.Restricted).To(HaveLen(1))
+		Expect(results.Restricted[0].Matches[0].Licence).To(Equal("Example-New-1.0"))
+	})
+
 	It("should find project licences not on the restricted list to be compliant", func() {
 		output, err := exec.Command(commandPath, "-A", "-r", "BSD", "testdata/MIT", "testdata/BSD3").CombinedOutput()
 		Expect(err).NotTo(HaveOccurred())
@@ -112,6 +204,18 @@ var _ = Describe("License Compliance Checker", func() {
 		Expect(results.Compliant[0].Project).To(Equal("testdata/BSD3"))
 	})
 
+	It("should check nested licences of a project as sub-components", func() {
+		output, err := exec.Command(commandPath, "-A", "-r", "BSD-3-Clause", "--check-nested-licences", "testdata/nested").CombinedOutput()
+		Expect(err).To(HaveOccurred())
+
+		results := resultsFromJSON(string(output))
+		Expect(results.Compliant).To(HaveLen(1))
+		Expect(results.Compliant[0].Project).To(Equal("testdata/nested"))
+		Expect(results.Restricted).To(HaveLen(1))
+		Expect(results.Restricted[0].Project).To(Equal("testdata/nested/third_party/bsd"))
+		Expect(results.Restricted[0].Parent).To(Equal("testdata/nested"))
+	})
+
 	Context("output", func() {
 		It("should not show anything with default options", func() {
 			output, err := exec.Command(commandPath, "-r", "BSD", "testdata/MIT").CombinedOutput()
@@ -168,7 +272,7 @@ var _ = Describe("License Compliance Checker", func() {
 
 			results := resultsFromJSON(string(output))
 			Expect(results.Restricted).To(HaveLen(5))
-			Expect(results.Restricted[0].Project).To(ContainSubstring("golang.org/x/crypto"))
+			Expect(modulesOf(results.Restricted)).To(ContainElement("golang.org/x/crypto"))
 		})
 
 		It("should fail with an overridden non-compliant module", func() {
@@ -196,8 +300,9 @@ var _ = Describe("License Compliance Checker", func() {
 
 			results := resultsFromJSON(string(output))
 			Expect(results.Restricted).To(HaveLen(4))
-			Expect(results.Restricted[0].Project).To(ContainSubstring("golang.org/x/net"))
-			Expect(results.Restricted[3].Project).To(ContainSubstring("github.com/sky-uk/licence-compliance-checker"))
+			Expect(modulesOf(results.Restricted)).To(ContainElement("golang.org/x/net"))
+			Expect(modulesOf(results.Restricted)).To(ContainElement("github.com/sky-uk/licence-compliance-checker/e2e/testdata/go-module"))
+			Expect(modulesOf(results.Restricted)).ToNot(ContainElement("golang.org/x/crypto"))
 		})
 
 		// Note: this test will fail if project isn't running inside the GOPATH
@@ -213,6 +318,259 @@ var _ = Describe("License Compliance Checker", func() {
 			Expect(string(output)).To(ContainSubstring("not using modules"))
 		})
 
+		It("should check vendored modules by module path and version", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "MIT", "--check-vendored-modules")
+			cmd.Dir = "testdata/vendored-module"
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted[0].Project).To(Equal("github.com/mit/project@v0.2.0"))
+			Expect(results.Restricted[0].Directory).To(Equal("vendor/github.com/mit/project"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Project).To(Equal("github.com/bsd/project@v1.0.0"))
+			Expect(results.UnlistedVendorDirectories).To(BeNil())
+		})
+
+		It("should annotate vendored modules as direct or indirect dependencies and apply their severity", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "MIT", "--indirect-severity", "warning", "--check-vendored-modules")
+			cmd.Dir = "testdata/vendored-module"
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted[0].Project).To(Equal("github.com/mit/project@v0.2.0"))
+			Expect(results.Restricted[0].Dependency).To(Equal("indirect"))
+			Expect(results.Restricted[0].Severity).To(Equal("warning"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Dependency).To(Equal("direct"))
+		})
+
+		It("should apply the severities of the policy, unless given as options", func() {
+			policyCmd := exec.Command(commandPath, "-A", "-r", "MIT", "--policy", "../severities/policy.json", "--check-vendored-modules")
+			policyCmd.Dir = "testdata/vendored-module"
+			optionCmd := exec.Command(commandPath, "-A", "-r", "MIT", "--policy", "../severities/policy.json", "--indirect-severity", "error", "--check-vendored-modules")
+			optionCmd.Dir = "testdata/vendored-module"
+
+			policyOutput, policyErr := policyCmd.CombinedOutput()
+			optionOutput, optionErr := optionCmd.CombinedOutput()
+			Expect(policyErr).NotTo(HaveOccurred())
+			Expect(optionErr).To(HaveOccurred())
+
+			policyResults := resultsFromJSON(string(policyOutput))
+			Expect(policyResults.Restricted).To(HaveLen(1))
+			Expect(policyResults.Restricted[0].Severity).To(Equal("warning"))
+			optionResults := resultsFromJSON(string(optionOutput))
+			Expect(optionResults.Restricted).To(HaveLen(1))
+			Expect(optionResults.Restricted[0].Severity).To(Equal("error"))
+		})
+
+		It("should ignore and override vendored modules by module path", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "MIT", "-r", "BSD-3-Clause", "-i", "github.com/mit/project", "-m", "github.com/bsd/project=Apache-2.0", "--check-vendored-modules")
+			cmd.Dir = "testdata/vendored-module"
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Ignored).To(HaveLen(1))
+			Expect(results.Ignored[0].Project).To(Equal("github.com/mit/project@v0.2.0"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Project).To(Equal("github.com/bsd/project@v1.0.0"))
+			Expect(results.Compliant[0].Matches[0].Licence).To(Equal("Apache-2.0"))
+		})
+
+		It("should check dep projects from Gopkg.lock by project name and version or revision", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "MIT", "--check-dep-projects")
+			cmd.Dir = "testdata/dep-project"
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted[0].Project).To(Equal("github.com/mit/project@v0.2.0"))
+			Expect(results.Restricted[0].Directory).To(Equal("vendor/github.com/mit/project"))
+			Expect(results.Restricted[0].Dependency).To(Equal("indirect"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Project).To(Equal("github.com/bsd/project@7fe510aff544695d5ca79be4cddc5dafd0e02fa4"))
+			Expect(results.Compliant[0].Dependency).To(Equal("direct"))
+			Expect(results.Unresolved).To(HaveLen(1))
+			Expect(results.Unresolved[0].Project).To(Equal("github.com/missing/project@v1.0.0"))
+		})
+
+		It("should ignore dep projects by project name", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "MIT", "-i", "github.com/mit/project", "-i", "github.com/missing/project", "--check-dep-projects")
+			cmd.Dir = "testdata/dep-project"
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Ignored).To(HaveLen(2))
+			Expect(results.Compliant).To(HaveLen(1))
+		})
+
+		It("should check the repository roots of a vendor directory and report orphaned directories", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "MIT", "-i", "github.com/mit/project", "--vendor-dir", "vendor")
+			cmd.Dir = "testdata/dep-project"
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Ignored).To(HaveLen(1))
+			Expect(results.Ignored[0].Project).To(Equal("vendor/github.com/mit/project"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Project).To(Equal("vendor/github.com/bsd/project"))
+			Expect(results.OrphanedVendorDirectories).To(Equal([]string{"vendor/example.com/orphan"}))
+		})
+
+		It("should check npm packages with their scope and declared licence", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "MIT", "--check-npm-packages", "testdata/npm-project")
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted[0].Project).To(Equal("mit-package@1.0.0"))
+			Expect(results.Restricted[0].Ecosystem).To(Equal("npm"))
+			Expect(results.Restricted[0].Scope).To(Equal("prod"))
+			Expect(results.Restricted[0].Dependency).To(Equal("direct"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Project).To(Equal("declared-package@0.2.0"))
+			Expect(results.Compliant[0].Scope).To(Equal("dev"))
+			Expect(results.Compliant[0].Matches[0].Licence).To(Equal("BSD-3-Clause"))
+		})
+
+		It("should check python distributions with their declared and detected licences", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "GPL-3.0-only", "-r", "GPL-3.0", "--check-python-packages", "testdata/python-venv")
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted[0].Project).To(Equal("gpl-package@0.1.0"))
+			Expect(results.Restricted[0].Ecosystem).To(Equal("python"))
+			Expect(results.Restricted[0].DeclaredLicence).To(Equal("GPL-3.0-only"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Project).To(Equal("mit-package@1.0.0"))
+			Expect(results.Compliant[0].DeclaredLicence).To(Equal("MIT"))
+			Expect(results.Compliant[0].Matches[0].Licence).To(Equal("MIT"))
+			Expect(results.Unresolved).To(HaveLen(1))
+			Expect(results.Unresolved[0].Project).To(Equal("broken_package@0.2.0"))
+			Expect(results.Unresolved[0].ErrStr).To(ContainSubstring("unable to read distribution metadata"))
+		})
+
+		It("should check maven artifacts from jars and POMs of the local repository", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "MIT", "--check-maven-dependencies", "testdata/maven/pom.xml", "--maven-repository", "testdata/maven/repository")
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted[0].Project).To(Equal("org.example:mit-lib:1.0"))
+			Expect(results.Restricted[0].Ecosystem).To(Equal("maven"))
+			Expect(results.Restricted[0].DeclaredLicence).To(Equal("MIT"))
+			Expect(results.Restricted[0].Matches[0].Licence).To(Equal("MIT"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Project).To(Equal("org.example:declared-lib:2.0"))
+			Expect(results.Compliant[0].Scope).To(Equal("dev"))
+			Expect(results.Compliant[0].Matches[0].Licence).To(Equal("BSD-3-Clause"))
+		})
+
+		It("should check cargo crates from the cargo registry cache", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "MIT", "--check-cargo-crates", "testdata/cargo")
+			cmd.Env = os.Environ()
+			cmd.Env = append(cmd.Env, "CARGO_HOME=testdata/cargo/cargo-home")
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted[0].Project).To(Equal("mit-crate@1.0.0"))
+			Expect(results.Restricted[0].Ecosystem).To(Equal("cargo"))
+			Expect(results.Restricted[0].Dependency).To(Equal("direct"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Project).To(Equal("declared-crate@0.3.0"))
+			Expect(results.Compliant[0].Dependency).To(Equal("indirect"))
+			Expect(results.Compliant[0].Matches[0].Licence).To(Equal("BSD-3-Clause"))
+		})
+
+		It("should check the OS packages of a root filesystem against their declared licences", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "GPL-3.0-or-later", "--check-os-packages", "testdata/rootfs")
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted[0].Project).To(Equal("bash@5.2.15-2+b2"))
+			Expect(results.Restricted[0].Ecosystem).To(Equal("deb"))
+			Expect(results.Restricted[0].DeclaredLicence).To(Equal("GPL-3.0-or-later"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Project).To(Equal("zlib1g@1:1.2.13.dfsg-1"))
+			Expect(results.Compliant[0].Matches[0].Licence).To(Equal("Zlib"))
+			Expect(results.Unidentifiable).To(HaveLen(1))
+			Expect(results.Unidentifiable[0].Project).To(Equal("tzdata@2024a-0+deb12u1"))
+		})
+
+		It("should check the projects of resolver plugins with the licences of detector plugins", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "MIT", "--policy", "testdata/plugins/policy.json")
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Restricted).To(HaveLen(1))
+			Expect(results.Restricted[0].Project).To(Equal("in-house/mit@1.0.0"))
+			Expect(results.Restricted[0].Ecosystem).To(Equal("in-house"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Project).To(Equal("in-house/proprietary@2.0.0"))
+			Expect(results.Compliant[0].Matches[0].Licence).To(Equal("LicenseRef-In-House"))
+		})
+
+		It("should not identify the licences detectors disagree on with the consensus detection", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "GPL-3.0", "--policy", "testdata/plugins/consensus.json")
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Unidentifiable).To(HaveLen(1))
+			Expect(results.Unidentifiable[0].Project).To(Equal("in-house/mit@1.0.0"))
+			Expect(results.Unidentifiable[0].ErrStr).To(Equal("detectors disagree: files: MIT, declared: Apache-2.0"))
+			Expect(results.DetectorsDisagree).To(HaveLen(1))
+			Expect(results.DetectorsDisagree[0].Project).To(Equal("in-house/mit@1.0.0"))
+			Expect(results.Compliant).To(HaveLen(1))
+			Expect(results.Compliant[0].Project).To(Equal("in-house/proprietary@2.0.0"))
+			Expect(results.Compliant[0].Matches[0].Detector).To(Equal("in-house-scanner"))
+		})
+
+		It("should fail on projects whose declared licence differs from their detected licence with the policy severity", func() {
+			cmd := exec.Command(commandPath, "-A", "-r", "GPL-3.0", "--policy", "testdata/plugins/mismatch.json")
+
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+
+			results := resultsFromJSON(string(output))
+			Expect(results.Restricted).To(BeNil())
+			Expect(results.Compliant).To(HaveLen(2))
+			Expect(results.Mismatched).To(HaveLen(1))
+			Expect(results.Mismatched[0].Project).To(Equal("in-house/mit@1.0.0"))
+			Expect(results.Mismatched[0].DeclaredLicence).To(Equal("Apache-2.0"))
+			Expect(results.Mismatched[0].Matches[0].Licence).To(Equal("MIT"))
+			Expect(results.Mismatched[0].Severity).To(Equal("error"))
+		})
+
 		It("should allow an overridden module using positional arguments", func() {
 			gopath := os.Getenv("GOPATH")
 			if gopath == "" {
@@ -241,3 +599,12 @@ func resultsFromJSON(document string) *compliance.Results {
 	Expect(err).NotTo(HaveOccurred())
 	return &v
 }
+
+// modulesOf returns the go modules of the results, whose order depends on the modules the project depends on
+func modulesOf(results []detection.Result) []string {
+	var modules []string
+	for _, result := range results {
+		modules = append(modules, result.Module)
+	}
+	return modules
+}
@@ -0,0 +1,3 @@
+// Copyright 2020 Jane Doe <jane@example.com>
+
+package main
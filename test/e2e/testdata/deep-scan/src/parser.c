@@ -0,0 +1,10 @@
+/*
+ * parser.c - a parser
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ */
+
+int parse(void) { return 0; }
@@ -0,0 +1,6 @@
+#!/bin/sh
+cat > /dev/null
+echo '{"protocolVersion": 1, "results": [
+  {"project": "in-house/mit@1.0.0", "ecosystem": "in-house", "module": "in-house/mit", "version": "1.0.0", "directory": "testdata/MIT", "declaredLicense": "Apache-2.0"},
+  {"project": "in-house/proprietary@2.0.0", "ecosystem": "in-house", "module": "in-house/proprietary", "version": "2.0.0", "directory": "testdata/no-licence"}
+]}'
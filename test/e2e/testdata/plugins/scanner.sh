@@ -0,0 +1,5 @@
+#!/bin/sh
+cat > /dev/null
+echo '{"protocolVersion": 1, "results": [
+  {"project": "testdata/no-licence", "matches": [{"license": "LicenseRef-In-House", "confidence": 1}]}
+]}'
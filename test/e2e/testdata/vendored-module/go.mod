@@ -0,0 +1,8 @@
+module example.com/vendored-module
+
+go 1.14
+
+require (
+	github.com/bsd/project v1.0.0
+	github.com/mit/project v0.2.0 // indirect
+)